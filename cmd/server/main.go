@@ -2,87 +2,183 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/backend"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
 	auth "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/authentication"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
 	upload "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/upload"
 	videocatalog "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/video-catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/health"
 	httpserver "github.com/sagarmaheshwary/microservices-api-gateway/internal/http"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/jaeger"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/shutdown"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
+	"google.golang.org/grpc"
 )
 
 func main() {
-	logger.Init()
+	// console logging until the config is loaded
+	logger.InitConsole()
 	cfg := config.NewConfig()
+	if err := logger.Init(logger.Options{
+		Format:   cfg.Logger.Format,
+		Level:    cfg.Logger.Level,
+		Sampling: logSampling(cfg.Logger.Sampling),
+	}); err != nil {
+		logger.Fatal().Err(err).Msg("Invalid logger config")
+	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	shutdownJaeger := jaeger.Init(ctx, cfg.Jaeger.URL)
 	prometheus.RegisterMetrics()
 
-	authClient, authConn, err := auth.NewClient(ctx, &auth.InitClientOptions{Config: cfg.GRPCAuthenticationClient})
+	authClient, authConn, err := auth.NewClient(ctx, &auth.InitClientOptions{Config: cfg.GRPCAuthenticationClient, SkipHealthCheck: cfg.GRPCConnect.Lazy})
 	if err != nil {
-		logger.Error("Failed to connect to auth client: %v", err)
+		logger.Error().Err(err).Msg("Failed to connect to auth client")
 		os.Exit(constant.ExitFailure)
 	}
-	defer authConn.Close()
+	go interceptor.WatchConnState(ctx, constant.ServiceAuthentication, authConn)
 
-	uploadClient, uploadConn, err := upload.NewClient(ctx, &upload.InitClientOptions{Config: cfg.GRPCUploadClient})
+	uploadClient, uploadConn, err := upload.NewClient(ctx, &upload.InitClientOptions{Config: cfg.GRPCUploadClient, SkipHealthCheck: cfg.GRPCConnect.Lazy})
 	if err != nil {
-		logger.Error("Failed to connect to upload client: %v", err)
+		logger.Error().Err(err).Msg("Failed to connect to upload client")
 		os.Exit(constant.ExitFailure)
 	}
-	defer uploadConn.Close()
+	go interceptor.WatchConnState(ctx, constant.ServiceUpload, uploadConn)
 
-	videoCatalogClient, videoCatalogConn, err := videocatalog.NewClient(ctx, &videocatalog.InitClientOptions{Config: cfg.GRPCVideoCatalogClient})
+	videoCatalogClient, videoCatalogConn, err := videocatalog.NewClient(ctx, &videocatalog.InitClientOptions{Config: cfg.GRPCVideoCatalogClient, SkipHealthCheck: cfg.GRPCConnect.Lazy})
 	if err != nil {
-		logger.Error("Failed to connect to video catalog client: %v", err)
+		logger.Error().Err(err).Msg("Failed to connect to video catalog client")
 		os.Exit(constant.ExitFailure)
 	}
-	defer videoCatalogConn.Close()
-
-	httpServer := httpserver.NewServer(
-		cfg.HTTPServer,
-		cfg.App.Env,
-		types.GRPCClients{
-			AuthClient:         authClient,
-			UploadClient:       uploadClient,
-			VideoCatalogClient: videoCatalogClient,
+	go interceptor.WatchConnState(ctx, constant.ServiceVideoCatalog, videoCatalogConn)
+
+	grpcClients := types.GRPCClients{
+		AuthClient:         authClient,
+		UploadClient:       uploadClient,
+		VideoCatalogClient: videoCatalogClient,
+		Conns: map[string]grpc.ClientConnInterface{
+			constant.ServiceAuthentication: authConn,
+			constant.ServiceUpload:         uploadConn,
+			constant.ServiceVideoCatalog:   videoCatalogConn,
 		},
-	)
+	}
+	if cfg.GRPCConnect.Lazy {
+		grpcClients.Ready = connectLazily(ctx, cfg.GRPCConnect, grpcClients)
+	}
+
+	if err := cfg.Health.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("Invalid health config")
+	}
+	prober := health.NewProber(httpserver.NewHealthChecker(cfg.Health, grpcClients), cfg.Health.ProbeInterval, cfg.Health.ProbeJitter)
+	go prober.Run(ctx)
+
+	httpServer := httpserver.NewServer(cfg, grpcClients, prober)
 
 	go func() {
-		if err := httpserver.Serve(httpServer, httpServer.ListenAndServe); err != nil && err != http.ErrServerClosed {
-			logger.Error("HTTP server error: %v", err)
+		if err := httpserver.Serve(httpServer); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error().Err(err).Msg("HTTP server error")
 			stop()
 		}
 	}()
 
+	var redirectServer *http.Server
+	if cfg.HTTPServer.TLS.Enabled && cfg.HTTPServer.TLS.RedirectPort > 0 {
+		redirectServer = httpserver.NewRedirectServer(cfg.HTTPServer)
+		go func() {
+			if err := httpserver.Serve(redirectServer); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error().Err(err).Msg("HTTP redirect server error")
+				stop()
+			}
+		}()
+	}
+
 	<-ctx.Done()
+	stop()
+
+	logger.Info().Msg("Shutdown signal received, send it again to exit immediately")
+
+	// a second signal exits even when the shutdown is stuck, restoring the
+	// default handlers wouldn't when the signal was ignored at startup
+	force := make(chan os.Signal, 1)
+	signal.Notify(force, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-force
+		logger.Warn().Str("signal", sig.String()).Msg("Second shutdown signal received, exiting")
+		os.Exit(constant.ExitFailure)
+	}()
 
-	logger.Info("Shutdown signal received")
+	coordinator := shutdown.New()
+	coordinator.Add("readiness", 0, func(ctx context.Context) error {
+		httpServer.SetKeepAlivesEnabled(false)
+		return prober.MarkShuttingDown(ctx)
+	})
+	coordinator.Add("drain", 0, shutdown.Wait(cfg.Shutdown.DrainPeriod))
+	coordinator.Add("http server", cfg.Shutdown.Timeout, func(ctx context.Context) error {
+		if redirectServer != nil {
+			return errors.Join(httpServer.Shutdown(ctx), redirectServer.Shutdown(ctx))
+		}
+		return httpServer.Shutdown(ctx)
+	})
+	coordinator.Add("grpc connections", 0, func(ctx context.Context) error {
+		return errors.Join(authConn.Close(), uploadConn.Close(), videoCatalogConn.Close())
+	})
+	coordinator.Add("tracer", 5*time.Second, shutdownJaeger)
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := shutdownJaeger(shutdownCtx); err != nil {
-		logger.Warn("failed to shutdown jaeger tracer: %v", err)
+	if err := coordinator.Shutdown(context.Background()); err != nil {
+		logger.Warn().Err(err).Msg("Shutdown finished with errors")
+		return
 	}
 
-	shutdownCtx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		logger.Warn("Http server shutdown error: %v", err)
+	logger.Info().Msg("Shutdown complete")
+}
+
+// connectLazily connects to the backends in the background, their routes are
+// answered with 503 until they are ready.
+func connectLazily(ctx context.Context, cfg *config.GRPCConnect, grpcClients types.GRPCClients) map[string]func() bool {
+	backoff := backend.Backoff{
+		Initial:    cfg.InitialBackoff,
+		Max:        cfg.MaxBackoff,
+		Multiplier: 2,
+		Jitter:     0.2,
 	}
 
-	logger.Info("Shutdown complete")
+	backends := []*backend.Backend{
+		backend.New(constant.ServiceAuthentication, grpcClients.AuthClient.Health, backoff),
+		backend.New(constant.ServiceUpload, grpcClients.UploadClient.Health, backoff),
+		backend.New(constant.ServiceVideoCatalog, grpcClients.VideoCatalogClient.Health, backoff),
+	}
+
+	ready := map[string]func() bool{}
+	for _, b := range backends {
+		ready[b.Name()] = b.Ready
+		go b.Connect(ctx)
+	}
+
+	return ready
+}
+
+func logSampling(cfg *config.LogSampling) *logger.Sampling {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return &logger.Sampling{
+		Burst:  uint32(cfg.Burst),
+		Period: cfg.Period,
+		Rate:   uint32(cfg.Rate),
+	}
 }
@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/health"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+)
+
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// Jitter varies every delay by up to +/- this fraction.
+	Jitter float64
+}
+
+// Backend tracks whether a gRPC backend has been reached since startup, so
+// the gateway can boot while it is down and serve its routes once it is up.
+type Backend struct {
+	name    string
+	check   health.CheckFunc
+	backoff Backoff
+	ready   atomic.Bool
+}
+
+func New(name string, check health.CheckFunc, backoff Backoff) *Backend {
+	return &Backend{name: name, check: check, backoff: backoff}
+}
+
+func (b *Backend) Name() string {
+	return b.name
+}
+
+// Ready reports whether Connect has reached the backend, it stays true
+// afterwards: later outages are handled by retries and the circuit breaker.
+func (b *Backend) Ready() bool {
+	return b.ready.Load()
+}
+
+// Connect checks the backend until it is healthy or ctx is done. The outcome
+// of the first attempt is logged as the backend's startup state.
+func (b *Backend) Connect(ctx context.Context) error {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := b.check(ctx)
+		if err == nil {
+			b.ready.Store(true)
+			logger.Info().
+				Str("service", b.name).
+				Int("attempts", attempt).
+				Dur("after", time.Since(start)).
+				Msg("Backend ready")
+			return nil
+		}
+
+		delay := b.delay(attempt)
+		if attempt == 1 {
+			logger.Warn().Str("service", b.name).Dur("retry_in", delay).Err(err).Msg("Backend unavailable, connecting in background")
+		} else {
+			logger.Debug().Str("service", b.name).Int("attempt", attempt).Dur("retry_in", delay).Err(err).Msg("Backend still unavailable")
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// delay returns the exponential backoff after the given failed attempt.
+func (b *Backend) delay(attempt int) time.Duration {
+	multiplier := b.backoff.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(b.backoff.Initial) * math.Pow(multiplier, float64(attempt-1))
+	if b.backoff.Max > 0 {
+		delay = math.Min(delay, float64(b.backoff.Max))
+	}
+
+	if b.backoff.Jitter > 0 {
+		delay *= 1 + b.backoff.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(delay)
+}
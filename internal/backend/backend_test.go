@@ -0,0 +1,54 @@
+package backend_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend_Connect(t *testing.T) {
+	tests := []struct {
+		name             string
+		failures         int32
+		expectedAttempts int32
+	}{
+		{name: "ready on first attempt", failures: 0, expectedAttempts: 1},
+		{name: "ready after retries", failures: 3, expectedAttempts: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			b := backend.New("test", func(ctx context.Context) error {
+				if attempts.Add(1) <= tt.failures {
+					return errors.New("unavailable")
+				}
+				return nil
+			}, backend.Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2})
+
+			assert.False(t, b.Ready())
+			require.NoError(t, b.Connect(context.Background()))
+
+			assert.True(t, b.Ready())
+			assert.Equal(t, tt.expectedAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestBackend_Connect_StopsWithContext(t *testing.T) {
+	b := backend.New("test", func(ctx context.Context) error {
+		return errors.New("unavailable")
+	}, backend.Backoff{Initial: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, b.Connect(ctx), context.DeadlineExceeded)
+	assert.False(t, b.Ready())
+}
@@ -0,0 +1,74 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewLRU[string, int](2, 0)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	_, _ = c.Get("a")
+	c.Set("c", 3)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok, "b should have been evicted")
+
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	v, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_TTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := cache.NewLRUWithClock[string, int](10, time.Minute, func() time.Time { return now })
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, 2*time.Minute)
+
+	now = now.Add(time.Minute)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok, "a should have expired")
+	assert.Equal(t, 1, c.Len())
+
+	v, ok := c.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+}
+
+func TestLRU_SetOverwrites(t *testing.T) {
+	c := cache.NewLRU[string, int](2, 0)
+
+	c.Set("a", 1)
+	c.Set("a", 2)
+
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestLRU_DeleteAndPurge(t *testing.T) {
+	c := cache.NewLRU[string, int](10, 0)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	c.Delete("a")
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+}
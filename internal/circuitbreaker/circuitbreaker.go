@@ -0,0 +1,227 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrOpen = errors.New("circuit breaker is open")
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type Settings struct {
+	// ConsecutiveFailures trips the breaker after that many failures in a
+	// row, 0 disables the check.
+	ConsecutiveFailures int
+	// FailureRate trips the breaker once the ratio of failed calls in the
+	// current Window reaches it (0-1), 0 disables the check.
+	FailureRate float64
+	// MinRequests is the number of calls needed in a Window before
+	// FailureRate is considered.
+	MinRequests int
+	// Window is how long the counts behind FailureRate are kept while the
+	// breaker is closed, failure streaks last until a call succeeds.
+	Window time.Duration
+	// OpenTimeout is how long the breaker stays open before letting probe
+	// calls through.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of probe calls allowed while half-open,
+	// the breaker closes once all of them succeed.
+	HalfOpenRequests int
+	OnStateChange    func(name string, from State, to State)
+}
+
+type counts struct {
+	requests             int
+	failures             int
+	consecutiveFailures  int
+	consecutiveSuccesses int
+}
+
+type Breaker struct {
+	name     string
+	settings Settings
+	now      func() time.Time
+
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	counts     counts
+	inFlight   int
+	expiry     time.Time
+}
+
+func New(name string, s Settings) *Breaker {
+	return NewWithClock(name, s, time.Now)
+}
+
+func NewWithClock(name string, s Settings, now func() time.Time) *Breaker {
+	if s.HalfOpenRequests <= 0 {
+		s.HalfOpenRequests = 1
+	}
+
+	b := &Breaker{name: name, settings: s, now: now}
+	b.toNewGeneration(now())
+
+	return b
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, _ := b.currentState(b.now())
+	return state
+}
+
+// Allow reports whether a call may proceed. When it may, the returned done
+// func must be called exactly once with the outcome of the call.
+func (b *Breaker) Allow() (done func(success bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, generation := b.currentState(b.now())
+
+	switch state {
+	case StateOpen:
+		return nil, ErrOpen
+	case StateHalfOpen:
+		if b.inFlight >= b.settings.HalfOpenRequests {
+			return nil, ErrOpen
+		}
+	}
+
+	b.inFlight++
+
+	return func(success bool) {
+		b.done(generation, success)
+	}, nil
+}
+
+func (b *Breaker) done(generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	state, current := b.currentState(now)
+	if generation != current {
+		// the call started before the last state change
+		return
+	}
+
+	b.inFlight--
+	b.counts.requests++
+
+	if success {
+		b.counts.consecutiveFailures = 0
+		b.counts.consecutiveSuccesses++
+
+		if state == StateHalfOpen && b.counts.consecutiveSuccesses >= b.settings.HalfOpenRequests {
+			b.setState(StateClosed, now)
+		}
+		return
+	}
+
+	b.counts.failures++
+	b.counts.consecutiveFailures++
+	b.counts.consecutiveSuccesses = 0
+
+	if state == StateHalfOpen || b.shouldTrip() {
+		b.setState(StateOpen, now)
+	}
+}
+
+func (b *Breaker) shouldTrip() bool {
+	c := b.counts
+
+	if b.settings.ConsecutiveFailures > 0 && c.consecutiveFailures >= b.settings.ConsecutiveFailures {
+		return true
+	}
+
+	if b.settings.FailureRate > 0 && c.requests >= b.settings.MinRequests {
+		return float64(c.failures)/float64(c.requests) >= b.settings.FailureRate
+	}
+
+	return false
+}
+
+func (b *Breaker) currentState(now time.Time) (State, uint64) {
+	switch b.state {
+	case StateClosed:
+		if !b.expiry.IsZero() && now.After(b.expiry) {
+			b.resetWindow(now)
+		}
+	case StateOpen:
+		if now.After(b.expiry) {
+			b.setState(StateHalfOpen, now)
+		}
+	}
+
+	return b.state, b.generation
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	if b.state == state {
+		return
+	}
+
+	prev := b.state
+	b.state = state
+	b.toNewGeneration(now)
+
+	if b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(b.name, prev, state)
+	}
+}
+
+func (b *Breaker) toNewGeneration(now time.Time) {
+	b.generation++
+	b.counts = counts{}
+	b.inFlight = 0
+
+	switch b.state {
+	case StateClosed:
+		if b.settings.Window > 0 {
+			b.expiry = now.Add(b.settings.Window)
+		} else {
+			b.expiry = time.Time{}
+		}
+	case StateOpen:
+		b.expiry = now.Add(b.settings.OpenTimeout)
+	default:
+		b.expiry = time.Time{}
+	}
+}
+
+// resetWindow starts a new counting window of the closed breaker. Only the
+// windowed counts are dropped, a backend failing across the boundary keeps
+// its streak and calls in flight still count.
+func (b *Breaker) resetWindow(now time.Time) {
+	b.counts.requests = 0
+	b.counts.failures = 0
+	b.expiry = now.Add(b.settings.Window)
+}
@@ -0,0 +1,160 @@
+package circuitbreaker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/circuitbreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+func call(t *testing.T, b *circuitbreaker.Breaker, success bool) {
+	t.Helper()
+
+	done, err := b.Allow()
+	require.NoError(t, err)
+	done(success)
+}
+
+func TestBreaker_ConsecutiveFailures(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	var transitions []string
+
+	b := circuitbreaker.NewWithClock("test", circuitbreaker.Settings{
+		ConsecutiveFailures: 3,
+		OpenTimeout:         10 * time.Second,
+		HalfOpenRequests:    1,
+		OnStateChange: func(name string, from, to circuitbreaker.State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	}, clock.Now)
+
+	call(t, b, false)
+	call(t, b, false)
+	call(t, b, true) // resets the streak
+	call(t, b, false)
+	call(t, b, false)
+	assert.Equal(t, circuitbreaker.StateClosed, b.State())
+
+	call(t, b, false)
+	assert.Equal(t, circuitbreaker.StateOpen, b.State())
+
+	_, err := b.Allow()
+	assert.ErrorIs(t, err, circuitbreaker.ErrOpen)
+
+	clock.Advance(11 * time.Second)
+	assert.Equal(t, circuitbreaker.StateHalfOpen, b.State())
+
+	// only one probe is let through while half-open
+	done, err := b.Allow()
+	require.NoError(t, err)
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, circuitbreaker.ErrOpen)
+
+	done(true)
+	assert.Equal(t, circuitbreaker.StateClosed, b.State())
+
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, transitions)
+}
+
+func TestBreaker_HalfOpenFailureReopens(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+
+	b := circuitbreaker.NewWithClock("test", circuitbreaker.Settings{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Second,
+		HalfOpenRequests:    2,
+	}, clock.Now)
+
+	call(t, b, false)
+	require.Equal(t, circuitbreaker.StateOpen, b.State())
+
+	clock.Advance(2 * time.Second)
+	call(t, b, true)
+	assert.Equal(t, circuitbreaker.StateHalfOpen, b.State())
+
+	call(t, b, false)
+	assert.Equal(t, circuitbreaker.StateOpen, b.State())
+}
+
+func TestBreaker_FailureRate(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+
+	b := circuitbreaker.NewWithClock("test", circuitbreaker.Settings{
+		FailureRate: 0.5,
+		MinRequests: 4,
+		Window:      time.Minute,
+		OpenTimeout: time.Second,
+	}, clock.Now)
+
+	call(t, b, false)
+	call(t, b, true)
+	call(t, b, false)
+	assert.Equal(t, circuitbreaker.StateClosed, b.State(), "below minimum requests")
+
+	// counts are dropped when the window rolls over
+	clock.Advance(2 * time.Minute)
+	call(t, b, false)
+	call(t, b, true)
+	call(t, b, true)
+	call(t, b, true)
+	assert.Equal(t, circuitbreaker.StateClosed, b.State())
+
+	call(t, b, false)
+	call(t, b, false)
+	assert.Equal(t, circuitbreaker.StateOpen, b.State())
+}
+
+func TestBreaker_IgnoresResultsFromPreviousState(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+
+	b := circuitbreaker.NewWithClock("test", circuitbreaker.Settings{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Second,
+	}, clock.Now)
+
+	slow, err := b.Allow()
+	require.NoError(t, err)
+
+	call(t, b, false)
+	require.Equal(t, circuitbreaker.StateOpen, b.State())
+
+	clock.Advance(2 * time.Second)
+	slow(true) // started while closed, must not close the half-open breaker
+	assert.Equal(t, circuitbreaker.StateHalfOpen, b.State())
+}
+
+func TestBreaker_StreakSurvivesWindowRollover(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+
+	b := circuitbreaker.NewWithClock("test", circuitbreaker.Settings{
+		ConsecutiveFailures: 3,
+		Window:              time.Minute,
+		OpenTimeout:         time.Second,
+	}, clock.Now)
+
+	call(t, b, false)
+	call(t, b, false)
+
+	// a call in flight when the window rolls over still counts
+	done, err := b.Allow()
+	require.NoError(t, err)
+	clock.Advance(2 * time.Minute)
+	assert.Equal(t, circuitbreaker.StateClosed, b.State())
+
+	done(false)
+	assert.Equal(t, circuitbreaker.StateOpen, b.State())
+}
@@ -1,11 +1,16 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"maps"
 	"os"
 	"path"
+	"slices"
 	"time"
 
 	"github.com/gofor-little/env"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 )
@@ -13,40 +18,277 @@ import (
 type Config struct {
 	HTTPServer               *HTTPServer
 	App                      *App
+	Logger                   *Logger
+	Health                   *Health
+	Shutdown                 *Shutdown
+	GRPCConnect              *GRPCConnect
 	GRPCAuthenticationClient *GRPCAuthenticationClient
 	GRPCUploadClient         *GRPCUploadClient
 	GRPCVideoCatalogClient   *GRPCVideoCatalogClient
 	Jaeger                   *Jaeger
+	RateLimit                *RateLimit
+	JWT                      *JWT
+	Transcoder               *Transcoder
+	ResponseCache            *ResponseCache
+}
+
+type Logger struct {
+	Format   string
+	Level    string
+	Sampling *LogSampling
+}
+
+type LogSampling struct {
+	Enabled bool
+	Burst   int
+	Period  time.Duration
+	Rate    int
+}
+
+type Shutdown struct {
+	// DrainPeriod is how long the gateway keeps serving after it reported not
+	// ready, so load balancers stop sending requests first.
+	DrainPeriod time.Duration
+	// Timeout bounds how long in-flight requests get to finish.
+	Timeout time.Duration
+}
+
+type Health struct {
+	// CriticalServices must be healthy for the gateway to be ready, the other
+	// services only degrade it.
+	CriticalServices []string
+	ProbeInterval    time.Duration
+	// ProbeJitter varies ProbeInterval by +/- this fraction.
+	ProbeJitter float64
+}
+
+// Validate checks the probe settings of h, a non positive interval would
+// probe the backends in a busy loop.
+func (h *Health) Validate() error {
+	var errs []error
+	if h.ProbeInterval <= 0 {
+		errs = append(errs, fmt.Errorf("health: probe interval must be positive, got %s", h.ProbeInterval))
+	}
+	if h.ProbeJitter < 0 || h.ProbeJitter >= 1 {
+		errs = append(errs, fmt.Errorf("health: probe jitter must be in [0, 1), got %g", h.ProbeJitter))
+	}
+
+	return errors.Join(errs...)
 }
 
 type HTTPServer struct {
-	Host string
-	Port int
+	Host       string
+	Port       int
+	RoutesFile string
+	TLS        *HTTPServerTLS
+	// ProblemDetailsByDefault writes errors as application/problem+json to
+	// clients that don't ask for application/json.
+	ProblemDetailsByDefault bool
+	// DefaultLocale is used for clients without a supported Accept-Language.
+	DefaultLocale string
+	// OpenAPIDocs serves a docs UI for /openapi.json at /docs.
+	OpenAPIDocs bool
+	// TrustedProxies are the IPs and CIDRs whose X-Forwarded-For and
+	// X-Real-IP headers are believed, by default none are and the client IP is
+	// the address of the peer.
+	TrustedProxies []string
+}
+
+// HTTPServerTLS terminates HTTPS on HTTPServer.Port, the certificate is
+// reloaded when it changes.
+type HTTPServerTLS struct {
+	Enabled      bool
+	CertFile     string
+	KeyFile      string
+	MinVersion   string
+	CipherSuites []string
+	// RedirectPort serves plain HTTP redirecting to HTTPS, 0 disables it.
+	RedirectPort int
 }
 
 type App struct {
 	Env string
 }
 
+// GRPCConnect controls how the gateway connects to its backends at startup.
+// Lazy starts the gateway with backends down and connects to them in the
+// background, otherwise it exits when one of them is unhealthy.
+type GRPCConnect struct {
+	Lazy           bool
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
 type GRPCAuthenticationClient struct {
-	URL     string
-	Timeout time.Duration
+	URL            string
+	Timeout        time.Duration
+	CircuitBreaker *CircuitBreaker
+	Retry          *Retry
+	LoadBalancing  *LoadBalancing
+	TLS            *TLS
+	TokenCache     *TokenCache
 }
 
 type GRPCUploadClient struct {
-	URL     string
-	Timeout time.Duration
+	URL            string
+	Timeout        time.Duration
+	CircuitBreaker *CircuitBreaker
+	Retry          *Retry
+	LoadBalancing  *LoadBalancing
+	TLS            *TLS
 }
 
 type GRPCVideoCatalogClient struct {
-	URL     string
-	Timeout time.Duration
+	URL            string
+	Timeout        time.Duration
+	CircuitBreaker *CircuitBreaker
+	Retry          *Retry
+	LoadBalancing  *LoadBalancing
+	TLS            *TLS
+	Coalescing     bool
+}
+
+// LoadBalancing spreads calls over the endpoints the client URL resolves to.
+type LoadBalancing struct {
+	Policy string
+	// HealthCheck watches grpc_health_v1 on every endpoint and stops sending
+	// calls to the unhealthy ones.
+	HealthCheck bool
+	// PollInterval is how often file:// and srv:// targets are resolved again,
+	// file:// targets are also watched and resolved as soon as the file changes.
+	PollInterval time.Duration
+}
+
+// TLS secures the connection to a backend, with CertFile and KeyFile the
+// gateway authenticates itself too (mTLS). The files are reloaded when they
+// change.
+type TLS struct {
+	Enabled bool
+	// CAFile verifies the backend, empty uses the system roots.
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+type CircuitBreaker struct {
+	Enabled             bool
+	ConsecutiveFailures int
+	FailureRate         float64
+	MinRequests         int
+	Window              time.Duration
+	OpenTimeout         time.Duration
+	HalfOpenRequests    int
+}
+
+type Retry struct {
+	Enabled        bool
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	RetryableCodes []string
+	HedgingDelay   time.Duration
+	// BudgetRatio is how many retries per call a client may send across all
+	// its calls, on top of BudgetMinPerSecond.
+	BudgetRatio        float64
+	BudgetMinPerSecond float64
+}
+
+type TokenCache struct {
+	Enabled bool
+	Size    int
+	TTL     time.Duration
 }
 
 type Jaeger struct {
 	URL string
 }
 
+type RateLimit struct {
+	Enabled   bool
+	Algorithm string
+	// Store keeps the counters: "memory" limits every replica on its own,
+	// "redis" shares them between replicas through the server at RedisURL.
+	Store         string
+	RedisURL      string
+	Auth          *RateLimitRule
+	Videos        *RateLimitRule
+	Authenticated *RateLimitRule
+}
+
+// Rules maps the names routes use in rate_limits to their rule, a nil rule
+// is disabled.
+func (r *RateLimit) Rules() map[string]*RateLimitRule {
+	return map[string]*RateLimitRule{
+		"auth":          r.Auth,
+		"videos":        r.Videos,
+		"authenticated": r.Authenticated,
+	}
+}
+
+type RateLimitRule struct {
+	Requests int
+	Window   time.Duration
+	KeyBy    string
+}
+
+// Validate checks the store and the rules of r.
+func (r *RateLimit) Validate() error {
+	var errs []error
+
+	switch r.Store {
+	case "", constant.RateLimitStoreMemory:
+	case constant.RateLimitStoreRedis:
+		if r.RedisURL == "" {
+			errs = append(errs, errors.New("rate limit: the redis store needs a redis url"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("rate limit: unknown store %q", r.Store))
+	}
+
+	rules := r.Rules()
+	for _, name := range slices.Sorted(maps.Keys(rules)) {
+		rule := rules[name]
+		if rule == nil {
+			continue
+		}
+		if rule.Requests <= 0 {
+			errs = append(errs, fmt.Errorf("rate limit %q: requests must be positive, got %d", name, rule.Requests))
+		}
+		if rule.Window <= 0 {
+			errs = append(errs, fmt.Errorf("rate limit %q: window must be positive, got %s", name, rule.Window))
+		}
+		if rule.KeyBy != constant.RateLimitKeyByIP && rule.KeyBy != constant.RateLimitKeyByUser {
+			errs = append(errs, fmt.Errorf("rate limit %q: unknown key %q", name, rule.KeyBy))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+type JWT struct {
+	VerifyMode          string
+	Secret              string
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	Issuer              string
+	Audience            string
+	Leeway              time.Duration
+}
+
+type Transcoder struct {
+	Reflection bool
+	// MaxBodyBytes bounds the JSON body of transcoded requests.
+	MaxBodyBytes int64
+}
+
+type ResponseCache struct {
+	Enabled bool
+	Size    int
+}
+
 type LoaderOptions struct {
 	EnvPath     string
 	EnvLoader   func(string) error
@@ -68,36 +310,178 @@ func NewConfigWithOptions(opts LoaderOptions) *Config {
 
 	if opts.EnvPath != "" && fileChecker(opts.EnvPath) {
 		if err := envLoader(opts.EnvPath); err != nil {
-			logger.Panic("Failed to load .env %q: %v", opts.EnvPath, err)
+			logger.Panic().Str("path", opts.EnvPath).Err(err).Msg("Failed to load .env")
 		}
-		logger.Info("Loaded environment variables from %q", opts.EnvPath)
+		logger.Info().Str("path", opts.EnvPath).Msg("Loaded environment variables")
 	} else {
-		logger.Info(".env file not found, using system environment variables")
+		logger.Info().Msg(".env file not found, using system environment variables")
 	}
 
 	return &Config{
 		HTTPServer: &HTTPServer{
-			Host: helper.GetEnv("HTTP_HOST", "localhost"),
-			Port: helper.GetEnvInt("HTTP_PORT", 4000),
+			Host:                    helper.GetEnv("HTTP_HOST", "localhost"),
+			Port:                    helper.GetEnvInt("HTTP_PORT", 4000),
+			RoutesFile:              helper.GetEnv("ROUTES_FILE", ""),
+			ProblemDetailsByDefault: helper.GetEnvBool("HTTP_PROBLEM_DETAILS_BY_DEFAULT", false),
+			DefaultLocale:           helper.GetEnv("HTTP_DEFAULT_LOCALE", "en"),
+			OpenAPIDocs:             helper.GetEnvBool("HTTP_OPENAPI_DOCS_ENABLED", false),
+			TrustedProxies:          helper.GetEnvSlice("HTTP_TRUSTED_PROXIES", nil),
+			TLS: &HTTPServerTLS{
+				Enabled:      helper.GetEnvBool("HTTP_TLS_ENABLED", false),
+				CertFile:     helper.GetEnv("HTTP_TLS_CERT_FILE", ""),
+				KeyFile:      helper.GetEnv("HTTP_TLS_KEY_FILE", ""),
+				MinVersion:   helper.GetEnv("HTTP_TLS_MIN_VERSION", "1.2"),
+				CipherSuites: helper.GetEnvSlice("HTTP_TLS_CIPHER_SUITES", nil),
+				RedirectPort: helper.GetEnvInt("HTTP_TLS_REDIRECT_PORT", 0),
+			},
 		},
 		App: &App{
 			Env: helper.GetEnv("APP_ENV", "development"),
 		},
+		Health: &Health{
+			CriticalServices: helper.GetEnvSlice("HEALTH_CRITICAL_SERVICES", []string{
+				constant.ServiceAuthentication,
+				constant.ServiceUpload,
+				constant.ServiceVideoCatalog,
+			}),
+			ProbeInterval: helper.GetEnvDurationSeconds("HEALTH_PROBE_INTERVAL_SECONDS", 10),
+			ProbeJitter:   helper.GetEnvFloat("HEALTH_PROBE_JITTER", 0.1),
+		},
+		Shutdown: &Shutdown{
+			DrainPeriod: helper.GetEnvDurationSeconds("SHUTDOWN_DRAIN_PERIOD_SECONDS", 5),
+			Timeout:     helper.GetEnvDurationSeconds("SHUTDOWN_TIMEOUT_SECONDS", 15),
+		},
+		Logger: &Logger{
+			Format: helper.GetEnv("LOG_FORMAT", "console"),
+			Level:  helper.GetEnv("LOG_LEVEL", "info"),
+			Sampling: &LogSampling{
+				Enabled: helper.GetEnvBool("LOG_SAMPLING_ENABLED", false),
+				Burst:   helper.GetEnvInt("LOG_SAMPLING_BURST", 100),
+				Period:  helper.GetEnvDurationSeconds("LOG_SAMPLING_PERIOD_SECONDS", 1),
+				Rate:    helper.GetEnvInt("LOG_SAMPLING_RATE", 10),
+			},
+		},
+		GRPCConnect: &GRPCConnect{
+			Lazy:           helper.GetEnvBool("GRPC_LAZY_CONNECT_ENABLED", false),
+			InitialBackoff: helper.GetEnvDurationMilliseconds("GRPC_CONNECT_INITIAL_BACKOFF_MS", 500),
+			MaxBackoff:     helper.GetEnvDurationSeconds("GRPC_CONNECT_MAX_BACKOFF_SECONDS", 30),
+		},
 		GRPCAuthenticationClient: &GRPCAuthenticationClient{
-			URL:     helper.GetEnv("GRPC_AUTHENTICATION_SERVICE_URL", "authentication-service:5001"),
-			Timeout: helper.GetEnvDurationSeconds("GRPC_AUTHENTICATION_SERVICE_TIMEOUT_SECONDS", 3),
+			URL:            helper.GetEnv("GRPC_AUTHENTICATION_SERVICE_URL", "authentication-service:5001"),
+			Timeout:        helper.GetEnvDurationSeconds("GRPC_AUTHENTICATION_SERVICE_TIMEOUT_SECONDS", 3),
+			CircuitBreaker: newCircuitBreaker("GRPC_AUTHENTICATION_SERVICE"),
+			Retry:          newRetry("GRPC_AUTHENTICATION_SERVICE"),
+			LoadBalancing:  newLoadBalancing("GRPC_AUTHENTICATION_SERVICE"),
+			TLS:            newTLS("GRPC_AUTHENTICATION_SERVICE"),
+			TokenCache: &TokenCache{
+				Enabled: helper.GetEnvBool("GRPC_AUTHENTICATION_SERVICE_TOKEN_CACHE_ENABLED", true),
+				Size:    helper.GetEnvInt("GRPC_AUTHENTICATION_SERVICE_TOKEN_CACHE_SIZE", 10000),
+				TTL:     helper.GetEnvDurationSeconds("GRPC_AUTHENTICATION_SERVICE_TOKEN_CACHE_TTL_SECONDS", 30),
+			},
 		},
 		GRPCUploadClient: &GRPCUploadClient{
-			URL:     helper.GetEnv("GRPC_UPLOAD_SERVICE_URL", "upload-service:5002"),
-			Timeout: helper.GetEnvDurationSeconds("GRPC_UPLOAD_SERVICE_TIMEOUT_SECONDS", 3),
+			URL:            helper.GetEnv("GRPC_UPLOAD_SERVICE_URL", "upload-service:5002"),
+			Timeout:        helper.GetEnvDurationSeconds("GRPC_UPLOAD_SERVICE_TIMEOUT_SECONDS", 3),
+			CircuitBreaker: newCircuitBreaker("GRPC_UPLOAD_SERVICE"),
+			Retry:          newRetry("GRPC_UPLOAD_SERVICE"),
+			LoadBalancing:  newLoadBalancing("GRPC_UPLOAD_SERVICE"),
+			TLS:            newTLS("GRPC_UPLOAD_SERVICE"),
 		},
 		GRPCVideoCatalogClient: &GRPCVideoCatalogClient{
-			URL:     helper.GetEnv("GRPC_VIDEO_CATALOG_SERVICE_URL", "video-catalog-service:5001"),
-			Timeout: helper.GetEnvDurationSeconds("GRPC_VIDEO_CATALOG_SERVICE_TIMEOUT_SECONDS", 3),
+			URL:            helper.GetEnv("GRPC_VIDEO_CATALOG_SERVICE_URL", "video-catalog-service:5001"),
+			Timeout:        helper.GetEnvDurationSeconds("GRPC_VIDEO_CATALOG_SERVICE_TIMEOUT_SECONDS", 3),
+			CircuitBreaker: newCircuitBreaker("GRPC_VIDEO_CATALOG_SERVICE"),
+			Retry:          newRetry("GRPC_VIDEO_CATALOG_SERVICE"),
+			LoadBalancing:  newLoadBalancing("GRPC_VIDEO_CATALOG_SERVICE"),
+			TLS:            newTLS("GRPC_VIDEO_CATALOG_SERVICE"),
+			Coalescing:     helper.GetEnvBool("GRPC_VIDEO_CATALOG_SERVICE_COALESCING_ENABLED", true),
 		},
 		Jaeger: &Jaeger{
 			URL: helper.GetEnv("JAEGER_URL", "jaeger:4318"),
 		},
+		RateLimit: &RateLimit{
+			Enabled:   helper.GetEnvBool("RATE_LIMIT_ENABLED", true),
+			Algorithm: helper.GetEnv("RATE_LIMIT_ALGORITHM", "token_bucket"),
+			Store:     helper.GetEnv("RATE_LIMIT_STORE", constant.RateLimitStoreMemory),
+			RedisURL:  helper.GetEnv("RATE_LIMIT_REDIS_URL", ""),
+			Auth: &RateLimitRule{
+				Requests: helper.GetEnvInt("RATE_LIMIT_AUTH_REQUESTS", 10),
+				Window:   helper.GetEnvDurationSeconds("RATE_LIMIT_AUTH_WINDOW_SECONDS", 60),
+				KeyBy:    helper.GetEnv("RATE_LIMIT_AUTH_KEY_BY", constant.RateLimitKeyByIP),
+			},
+			Videos: &RateLimitRule{
+				Requests: helper.GetEnvInt("RATE_LIMIT_VIDEOS_REQUESTS", 100),
+				Window:   helper.GetEnvDurationSeconds("RATE_LIMIT_VIDEOS_WINDOW_SECONDS", 60),
+				KeyBy:    helper.GetEnv("RATE_LIMIT_VIDEOS_KEY_BY", constant.RateLimitKeyByIP),
+			},
+			Authenticated: &RateLimitRule{
+				Requests: helper.GetEnvInt("RATE_LIMIT_AUTHENTICATED_REQUESTS", 60),
+				Window:   helper.GetEnvDurationSeconds("RATE_LIMIT_AUTHENTICATED_WINDOW_SECONDS", 60),
+				KeyBy:    helper.GetEnv("RATE_LIMIT_AUTHENTICATED_KEY_BY", constant.RateLimitKeyByUser),
+			},
+		},
+		JWT: &JWT{
+			VerifyMode:          helper.GetEnv("JWT_VERIFY_MODE", constant.JWTVerifyModeRemote),
+			Secret:              helper.GetEnv("JWT_SECRET", ""),
+			JWKSURL:             helper.GetEnv("JWT_JWKS_URL", ""),
+			JWKSRefreshInterval: helper.GetEnvDurationSeconds("JWT_JWKS_REFRESH_INTERVAL_SECONDS", 300),
+			Issuer:              helper.GetEnv("JWT_ISSUER", ""),
+			Audience:            helper.GetEnv("JWT_AUDIENCE", ""),
+			Leeway:              helper.GetEnvDurationSeconds("JWT_LEEWAY_SECONDS", 0),
+		},
+		Transcoder: &Transcoder{
+			Reflection:   helper.GetEnvBool("TRANSCODER_REFLECTION_ENABLED", false),
+			MaxBodyBytes: int64(helper.GetEnvInt("TRANSCODER_MAX_BODY_BYTES", 1<<20)),
+		},
+		ResponseCache: &ResponseCache{
+			Enabled: helper.GetEnvBool("RESPONSE_CACHE_ENABLED", true),
+			Size:    helper.GetEnvInt("RESPONSE_CACHE_SIZE", 1000),
+		},
+	}
+}
+
+func newCircuitBreaker(prefix string) *CircuitBreaker {
+	return &CircuitBreaker{
+		Enabled:             helper.GetEnvBool(prefix+"_CIRCUIT_BREAKER_ENABLED", true),
+		ConsecutiveFailures: helper.GetEnvInt(prefix+"_CIRCUIT_BREAKER_CONSECUTIVE_FAILURES", 5),
+		FailureRate:         helper.GetEnvFloat(prefix+"_CIRCUIT_BREAKER_FAILURE_RATE", 0.5),
+		MinRequests:         helper.GetEnvInt(prefix+"_CIRCUIT_BREAKER_MIN_REQUESTS", 20),
+		Window:              helper.GetEnvDurationSeconds(prefix+"_CIRCUIT_BREAKER_WINDOW_SECONDS", 60),
+		OpenTimeout:         helper.GetEnvDurationSeconds(prefix+"_CIRCUIT_BREAKER_OPEN_TIMEOUT_SECONDS", 30),
+		HalfOpenRequests:    helper.GetEnvInt(prefix+"_CIRCUIT_BREAKER_HALF_OPEN_REQUESTS", 1),
+	}
+}
+
+func newRetry(prefix string) *Retry {
+	return &Retry{
+		Enabled:            helper.GetEnvBool(prefix+"_RETRY_ENABLED", true),
+		MaxAttempts:        helper.GetEnvInt(prefix+"_RETRY_MAX_ATTEMPTS", 3),
+		InitialBackoff:     helper.GetEnvDurationMilliseconds(prefix+"_RETRY_INITIAL_BACKOFF_MS", 50),
+		MaxBackoff:         helper.GetEnvDurationMilliseconds(prefix+"_RETRY_MAX_BACKOFF_MS", 500),
+		Multiplier:         helper.GetEnvFloat(prefix+"_RETRY_BACKOFF_MULTIPLIER", 2),
+		Jitter:             helper.GetEnvFloat(prefix+"_RETRY_JITTER", 0.2),
+		RetryableCodes:     helper.GetEnvSlice(prefix+"_RETRY_CODES", []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED", "ABORTED"}),
+		HedgingDelay:       helper.GetEnvDurationMilliseconds(prefix+"_RETRY_HEDGING_DELAY_MS", 0),
+		BudgetRatio:        helper.GetEnvFloat(prefix+"_RETRY_BUDGET_RATIO", 0.1),
+		BudgetMinPerSecond: helper.GetEnvFloat(prefix+"_RETRY_BUDGET_MIN_PER_SECOND", 10),
+	}
+}
+
+func newLoadBalancing(prefix string) *LoadBalancing {
+	return &LoadBalancing{
+		Policy:       helper.GetEnv(prefix+"_LOAD_BALANCING_POLICY", constant.LoadBalancingRoundRobin),
+		HealthCheck:  helper.GetEnvBool(prefix+"_HEALTH_CHECK_ENABLED", true),
+		PollInterval: helper.GetEnvDurationSeconds(prefix+"_DISCOVERY_POLL_INTERVAL_SECONDS", 10),
+	}
+}
+
+func newTLS(prefix string) *TLS {
+	return &TLS{
+		Enabled:    helper.GetEnvBool(prefix+"_TLS_ENABLED", false),
+		CAFile:     helper.GetEnv(prefix+"_TLS_CA_FILE", ""),
+		CertFile:   helper.GetEnv(prefix+"_TLS_CERT_FILE", ""),
+		KeyFile:    helper.GetEnv(prefix+"_TLS_KEY_FILE", ""),
+		ServerName: helper.GetEnv(prefix+"_TLS_SERVER_NAME", ""),
 	}
 }
 
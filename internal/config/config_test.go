@@ -68,3 +68,65 @@ func TestNewConfigWithOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimit_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		store       string
+		redisURL    string
+		rule        *config.RateLimitRule
+		expectedErr string
+	}{
+		{name: "valid", rule: &config.RateLimitRule{Requests: 10, Window: time.Minute, KeyBy: "ip"}},
+		{name: "disabled", rule: nil},
+		{name: "zero requests", rule: &config.RateLimitRule{Requests: 0, Window: time.Minute, KeyBy: "ip"}, expectedErr: `rate limit "auth": requests must be positive`},
+		{name: "negative window", rule: &config.RateLimitRule{Requests: 10, Window: -time.Second, KeyBy: "user"}, expectedErr: `rate limit "auth": window must be positive`},
+		{name: "zero window", rule: &config.RateLimitRule{Requests: 10, KeyBy: "ip"}, expectedErr: `rate limit "auth": window must be positive`},
+		{name: "unknown key", rule: &config.RateLimitRule{Requests: 10, Window: time.Minute, KeyBy: "header"}, expectedErr: `rate limit "auth": unknown key "header"`},
+		{name: "memory store", store: "memory"},
+		{name: "redis store", store: "redis", redisURL: "redis://localhost:6379/0"},
+		{name: "redis store without url", store: "redis", expectedErr: "rate limit: the redis store needs a redis url"},
+		{name: "unknown store", store: "memcached", expectedErr: `rate limit: unknown store "memcached"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&config.RateLimit{Enabled: true, Store: tt.store, RedisURL: tt.redisURL, Auth: tt.rule}).Validate()
+
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestHealth_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		health      config.Health
+		expectedErr string
+	}{
+		{name: "valid", health: config.Health{ProbeInterval: 10 * time.Second, ProbeJitter: 0.1}},
+		{name: "no jitter", health: config.Health{ProbeInterval: 10 * time.Second}},
+		{name: "zero interval", health: config.Health{ProbeJitter: 0.1}, expectedErr: "probe interval must be positive"},
+		{name: "negative interval", health: config.Health{ProbeInterval: -time.Second}, expectedErr: "probe interval must be positive"},
+		{name: "negative jitter", health: config.Health{ProbeInterval: time.Second, ProbeJitter: -0.1}, expectedErr: "probe jitter must be in [0, 1)"},
+		{name: "jitter of one", health: config.Health{ProbeInterval: time.Second, ProbeJitter: 1}, expectedErr: "probe jitter must be in [0, 1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.health.Validate()
+
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
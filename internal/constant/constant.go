@@ -2,37 +2,94 @@ package constant
 
 // response messages
 const (
-	MessageOK                  = "Success"
-	MessageCreated             = "Created New Resource"
-	MessageBadRequest          = "Bad Request"
-	MessageUnauthorized        = "Unauthorized"
-	MessageForbidden           = "Forbidden"
-	MessageNotFound            = "Resource Not Found"
-	MessageConflict            = "Conflict"
-	MessageInternalServerError = "Internal Server Error"
-	MessageServiceUnavailable  = "Service Unavailable"
+	MessageOK                    = "Success"
+	MessageCreated               = "Created New Resource"
+	MessageBadRequest            = "Bad Request"
+	MessageUnauthorized          = "Unauthorized"
+	MessageForbidden             = "Forbidden"
+	MessageNotFound              = "Resource Not Found"
+	MessageConflict              = "Conflict"
+	MessageRequestEntityTooLarge = "Request Entity Too Large"
+	MessageTooManyRequests       = "Too Many Requests"
+	MessageClientClosedRequest   = "Client Closed Request"
+	MessageInternalServerError   = "Internal Server Error"
+	MessageNotImplemented        = "Not Implemented"
+	MessageServiceUnavailable    = "Service Unavailable"
+	MessageGatewayTimeout        = "Gateway Timeout"
 )
 
+// StatusClientClosedRequest is the non-standard status of requests the
+// client gave up on, net/http has no constant for it.
+const StatusClientClosedRequest = 499
+
 const (
 	MessageServicesUnhealthy = "Some services are not available!"
 	MessageServicesHealthy   = "All services are healthy!"
+	MessageAlive             = "Gateway is alive!"
+	MessageReady             = "Gateway is ready!"
+	MessageNotReady          = "Gateway is not ready!"
 )
 
 const (
-	HealthStatusHealthy  = "healthy"
-	HealthStatusDegraded = "degraded"
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+	// the gateway is draining connections before it stops
+	HealthStatusShuttingDown = "shutting_down"
 )
 
 // gRPC metadata headers
 const (
-	GRPCHeaderAuthorization = "authorization"
-	GRPCHeaderUserId        = "x-user-id"
+	GRPCHeaderAuthorization  = "authorization"
+	GRPCHeaderUserId         = "x-user-id"
+	GRPCHeaderRequestId      = "x-request-id"
+	GRPCHeaderAcceptLanguage = "accept-language"
+)
+
+// HTTP headers
+const (
+	HeaderRequestId       = "X-Request-ID"
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderContentLanguage = "Content-Language"
 )
 
 const (
 	AuthUser = "user"
+	// set by ProblemDetailsMiddleware when errors are written as problem+json
+	ProblemDetails = "problem_details"
+)
+
+// rate limit keys
+const (
+	RateLimitKeyByIP   = "ip"
+	RateLimitKeyByUser = "user"
+
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreRedis  = "redis"
+)
+
+// token verification modes
+const (
+	JWTVerifyModeRemote            = "remote"
+	JWTVerifyModeLocal             = "local"
+	JWTVerifyModeLocalWithFallback = "local_with_fallback"
+)
+
+// gRPC client load balancing policies
+const (
+	LoadBalancingPickFirst    = "pick_first"
+	LoadBalancingRoundRobin   = "round_robin"
+	LoadBalancingLeastRequest = "least_request"
 )
 
 const ServiceName = "API Gateway"
 
+// downstream gRPC services, ServiceGateway is used by routes the gateway serves itself
+const (
+	ServiceGateway        = "gateway"
+	ServiceAuthentication = "authentication"
+	ServiceUpload         = "upload"
+	ServiceVideoCatalog   = "video_catalog"
+)
+
 const ExitFailure = 1
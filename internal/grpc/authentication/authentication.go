@@ -6,6 +6,7 @@ import (
 
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
@@ -40,7 +41,6 @@ func (a *AuthenticationClient) Register(ctx context.Context, in *authpb.Register
 
 	response, err := a.client.Register(ctx, in)
 	if err != nil {
-		logger.Error("gRPC authenticationClient.Register failed %v", err)
 		return nil, err
 	}
 
@@ -53,7 +53,6 @@ func (a *AuthenticationClient) Login(ctx context.Context, in *authpb.LoginReques
 
 	response, err := a.client.Login(ctx, in)
 	if err != nil {
-		logger.Error("gRPC authenticationClient.Login failed %v", err)
 		return nil, err
 	}
 
@@ -67,9 +66,8 @@ func (a *AuthenticationClient) VerifyToken(ctx context.Context, in *authpb.Verif
 	md := metadata.Pairs(constant.GRPCHeaderAuthorization, token)
 	ctx = metadata.NewOutgoingContext(ctx, md)
 
-	response, err := a.client.VerifyToken(ctx, in)
+	response, err := a.client.VerifyToken(ctx, in, interceptor.Retryable())
 	if err != nil {
-		logger.Error("gRPC authenticationClient.VerifyToken failed %v", err)
 		return nil, err
 	}
 
@@ -85,7 +83,6 @@ func (a *AuthenticationClient) Logout(ctx context.Context, in *authpb.LogoutRequ
 
 	response, err := a.client.Logout(ctx, in)
 	if err != nil {
-		logger.Error("gRPC authenticationClient.Logout failed %v", err)
 		return nil, err
 	}
 
@@ -98,12 +95,14 @@ func (a *AuthenticationClient) Health(ctx context.Context) error {
 
 	res, err := a.health.Check(ctx, &healthpb.HealthCheckRequest{})
 	if err != nil {
-		logger.Error("Authentication gRPC health check failed! %v", err)
 		return err
 	}
 
 	if res.Status == healthpb.HealthCheckResponse_NOT_SERVING {
-		logger.Error("Authentication gRPC health check failed")
+		logger.Ctx(ctx).Error().
+			Str("service", constant.ServiceAuthentication).
+			Str("status", res.Status.String()).
+			Msg("gRPC health check failed")
 		return errors.New("authentication grpc health check failed")
 	}
 
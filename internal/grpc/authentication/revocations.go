@@ -0,0 +1,81 @@
+package authentication
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// revocations remembers logged out tokens until they expire. Unlike the token
+// cache it is not size bounded, a revoked token must never become valid again
+// because newer logouts pushed it out. Expired entries are swept in expiry
+// order whenever the set is used.
+type revocations struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	expires map[string]time.Time
+	queue   expiryQueue
+}
+
+func newRevocations(now func() time.Time) *revocations {
+	return &revocations{now: now, expires: map[string]time.Time{}}
+}
+
+// add revokes key until the given time, a key already revoked keeps the
+// later of both.
+func (r *revocations) add(key string, until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep()
+
+	if current, ok := r.expires[key]; ok && !until.After(current) {
+		return
+	}
+	r.expires[key] = until
+	heap.Push(&r.queue, revocation{key: key, expiresAt: until})
+}
+
+func (r *revocations) has(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep()
+
+	_, ok := r.expires[key]
+	return ok
+}
+
+func (r *revocations) sweep() {
+	now := r.now()
+
+	for r.queue.Len() > 0 && !now.Before(r.queue[0].expiresAt) {
+		item := heap.Pop(&r.queue).(revocation)
+		// the key may have been revoked again for longer, its newer entry is
+		// still queued
+		if r.expires[item.key].Equal(item.expiresAt) {
+			delete(r.expires, item.key)
+		}
+	}
+}
+
+type revocation struct {
+	key       string
+	expiresAt time.Time
+}
+
+// expiryQueue is a min heap of revocations ordered by expiry.
+type expiryQueue []revocation
+
+func (q expiryQueue) Len() int           { return len(q) }
+func (q expiryQueue) Less(i, j int) bool { return q[i].expiresAt.Before(q[j].expiresAt) }
+func (q expiryQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *expiryQueue) Push(x any)        { *q = append(*q, x.(revocation)) }
+
+func (q *expiryQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
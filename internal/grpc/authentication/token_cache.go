@@ -0,0 +1,129 @@
+package authentication
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/cache"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/jwt"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
+	"google.golang.org/protobuf/proto"
+)
+
+// CachedAuthenticationClient remembers successful VerifyToken responses for a
+// short time so repeated requests with the same bearer token skip the gRPC
+// call. Tokens are evicted as soon as they are logged out, and remembered as
+// revoked until they expire so local verification can reject them too.
+type CachedAuthenticationClient struct {
+	AuthenticationService
+	ttl     time.Duration
+	now     func() time.Time
+	tokens  *cache.LRU[string, *authpb.VerifyTokenResponse]
+	revoked *revocations
+
+	// mu orders caching a response against revoking its token, so a logout
+	// can't be undone by a verification that was in flight
+	mu sync.Mutex
+}
+
+func NewCachedAuthenticationClient(next AuthenticationService, size int, ttl time.Duration) *CachedAuthenticationClient {
+	return NewCachedAuthenticationClientWithClock(next, size, ttl, time.Now)
+}
+
+func NewCachedAuthenticationClientWithClock(next AuthenticationService, size int, ttl time.Duration, now func() time.Time) *CachedAuthenticationClient {
+	return &CachedAuthenticationClient{
+		AuthenticationService: next,
+		ttl:                   ttl,
+		now:                   now,
+		tokens:                cache.NewLRUWithClock[string, *authpb.VerifyTokenResponse](size, ttl, now),
+		revoked:               newRevocations(now),
+	}
+}
+
+func (a *CachedAuthenticationClient) VerifyToken(ctx context.Context, in *authpb.VerifyTokenRequest, token string) (*authpb.VerifyTokenResponse, error) {
+	key := tokenKey(token)
+
+	if res, ok := a.tokens.Get(key); ok {
+		prometheus.TokenCacheRequests.WithLabelValues("hit").Inc()
+		// every hit gets its own copy, callers are free to modify it
+		return proto.Clone(res).(*authpb.VerifyTokenResponse), nil
+	}
+	prometheus.TokenCacheRequests.WithLabelValues("miss").Inc()
+
+	res, err := a.AuthenticationService.VerifyToken(ctx, in, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// the service accepted the token, so its exp can be trusted to end the
+	// entry before the token does
+	ttl := a.ttl
+	if untilExpiry, ok := a.untilExpiry(token); ok && (ttl <= 0 || untilExpiry < ttl) {
+		ttl = untilExpiry
+	}
+	if ttl <= 0 {
+		return res, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// a logout that raced with this call must not be undone by caching a
+	// response the authentication service produced before the token was revoked
+	if !a.revoked.has(key) {
+		a.tokens.SetWithTTL(key, proto.Clone(res).(*authpb.VerifyTokenResponse), ttl)
+	}
+
+	return res, nil
+}
+
+func (a *CachedAuthenticationClient) Logout(ctx context.Context, in *authpb.LogoutRequest, token string) (*authpb.LogoutResponse, error) {
+	res, err := a.AuthenticationService.Logout(ctx, in, token)
+	if err != nil {
+		// the token is still valid upstream, so it stays valid here too
+		return nil, err
+	}
+
+	// a token without exp never passes local verification, it only has to
+	// outlive a cached response
+	until := a.now().Add(a.ttl)
+	if untilExpiry, ok := a.untilExpiry(token); ok && untilExpiry > a.ttl {
+		until = a.now().Add(untilExpiry)
+	}
+
+	key := tokenKey(token)
+
+	a.mu.Lock()
+	a.revoked.add(key, until)
+	a.tokens.Delete(key)
+	a.mu.Unlock()
+
+	return res, nil
+}
+
+// Revoked reports whether token was logged out through a, it is remembered
+// until the token expires.
+func (a *CachedAuthenticationClient) Revoked(token string) bool {
+	return a.revoked.has(tokenKey(token))
+}
+
+// untilExpiry is how long token is valid for, ok is false when it has no exp.
+func (a *CachedAuthenticationClient) untilExpiry(token string) (time.Duration, bool) {
+	claims, err := jwt.ParseUnverified(token)
+	if err != nil || claims.ExpiresAt.IsZero() {
+		return 0, false
+	}
+
+	return max(claims.ExpiresAt.Sub(a.now()), 0), true
+}
+
+// tokenKey hashes the token so raw credentials are never held in memory
+// longer than the request that carried them.
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,235 @@
+package authentication_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	auth "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/authentication"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/jwt"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newCachedClient(mockClient *MockAuthenticationServiceClient) *auth.CachedAuthenticationClient {
+	return newCachedClientWithClock(mockClient, time.Now)
+}
+
+func newCachedClientWithClock(mockClient *MockAuthenticationServiceClient, now func() time.Time) *auth.CachedAuthenticationClient {
+	cfg := &config.GRPCAuthenticationClient{Timeout: 2 * time.Second}
+	return auth.NewCachedAuthenticationClientWithClock(auth.NewAuthenticationClient(mockClient, nil, cfg), 10, time.Minute, now)
+}
+
+func signHS256(t *testing.T, secret []byte, claims map[string]any) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(map[string]any{"alg": "HS256", "typ": "JWT"}) + "." + encode(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestCachedAuthenticationClient_VerifyToken(t *testing.T) {
+	res := &authpb.VerifyTokenResponse{
+		Message: constant.MessageOK,
+		Data:    &authpb.VerifyTokenResponseData{User: dummyUser},
+	}
+
+	mockClient := new(MockAuthenticationServiceClient)
+	mockClient.On("VerifyToken", mock.Anything, mock.Anything).Return(res, nil).Once()
+
+	client := newCachedClient(mockClient)
+
+	for range 3 {
+		got, err := client.VerifyToken(context.Background(), &authpb.VerifyTokenRequest{}, "Bearer token")
+		require.NoError(t, err)
+		assert.Equal(t, res, got)
+	}
+
+	mockClient.AssertNumberOfCalls(t, "VerifyToken", 1)
+}
+
+func TestCachedAuthenticationClient_DoesNotCacheErrors(t *testing.T) {
+	mockClient := new(MockAuthenticationServiceClient)
+	mockClient.On("VerifyToken", mock.Anything, mock.Anything).Return(nil, errors.New("unauthenticated"))
+
+	client := newCachedClient(mockClient)
+
+	for range 2 {
+		_, err := client.VerifyToken(context.Background(), &authpb.VerifyTokenRequest{}, "Bearer token")
+		assert.Error(t, err)
+	}
+
+	mockClient.AssertNumberOfCalls(t, "VerifyToken", 2)
+}
+
+func TestCachedAuthenticationClient_LogoutEvictsToken(t *testing.T) {
+	res := &authpb.VerifyTokenResponse{
+		Message: constant.MessageOK,
+		Data:    &authpb.VerifyTokenResponseData{User: dummyUser},
+	}
+
+	mockClient := new(MockAuthenticationServiceClient)
+	mockClient.On("VerifyToken", mock.Anything, mock.Anything).Return(res, nil).Once()
+	mockClient.On("Logout", mock.Anything, mock.Anything).Return(&authpb.LogoutResponse{Message: constant.MessageOK}, nil)
+	mockClient.On("VerifyToken", mock.Anything, mock.Anything).Return(nil, errors.New("unauthenticated"))
+
+	client := newCachedClient(mockClient)
+	ctx := context.Background()
+
+	_, err := client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, "Bearer token")
+	require.NoError(t, err)
+
+	_, err = client.Logout(ctx, &authpb.LogoutRequest{}, "Bearer token")
+	require.NoError(t, err)
+
+	_, err = client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, "Bearer token")
+	assert.Error(t, err)
+	mockClient.AssertNumberOfCalls(t, "VerifyToken", 2)
+}
+
+func TestCachedAuthenticationClient_EntryEndsWithToken(t *testing.T) {
+	res := &authpb.VerifyTokenResponse{
+		Message: constant.MessageOK,
+		Data:    &authpb.VerifyTokenResponseData{User: dummyUser},
+	}
+
+	mockClient := new(MockAuthenticationServiceClient)
+	mockClient.On("VerifyToken", mock.Anything, mock.Anything).Return(res, nil)
+
+	clock := time.Now()
+	client := newCachedClientWithClock(mockClient, func() time.Time { return clock })
+	token := "Bearer " + signHS256(t, []byte("secret"), map[string]any{"sub": "1", "exp": clock.Add(10 * time.Second).Unix()})
+	ctx := context.Background()
+
+	_, err := client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, token)
+	require.NoError(t, err)
+	_, err = client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, token)
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "VerifyToken", 1)
+
+	// the token expires before the cache ttl of a minute
+	clock = clock.Add(11 * time.Second)
+	_, err = client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, token)
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "VerifyToken", 2)
+}
+
+func TestCachedAuthenticationClient_LogoutRevokesLocallyVerifiedToken(t *testing.T) {
+	mockClient := new(MockAuthenticationServiceClient)
+	mockClient.On("Logout", mock.Anything, mock.Anything).Return(&authpb.LogoutResponse{Message: constant.MessageOK}, nil)
+
+	secret := []byte("secret")
+	clock := time.Now()
+	client := newCachedClientWithClock(mockClient, func() time.Time { return clock })
+	verify := middleware.LocalVerifyToken(jwt.NewVerifier(jwt.Options{Secret: secret}), nil, client.Revoked)
+
+	token := "Bearer " + signHS256(t, secret, map[string]any{"sub": "1", "exp": clock.Add(time.Hour).Unix()})
+	ctx := context.Background()
+
+	_, err := verify(ctx, &authpb.VerifyTokenRequest{}, token)
+	require.NoError(t, err)
+
+	_, err = client.Logout(ctx, &authpb.LogoutRequest{}, token)
+	require.NoError(t, err)
+
+	_, err = verify(ctx, &authpb.VerifyTokenRequest{}, token)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the revocation outlives the cache ttl of a minute, until the token expires
+	clock = clock.Add(30 * time.Minute)
+	assert.True(t, client.Revoked(token))
+	clock = clock.Add(31 * time.Minute)
+	assert.False(t, client.Revoked(token))
+
+	mockClient.AssertNotCalled(t, "VerifyToken", mock.Anything, mock.Anything)
+}
+
+func TestCachedAuthenticationClient_RevocationsAreNotBounded(t *testing.T) {
+	mockClient := new(MockAuthenticationServiceClient)
+	mockClient.On("Logout", mock.Anything, mock.Anything).Return(&authpb.LogoutResponse{Message: constant.MessageOK}, nil)
+
+	clock := time.Now()
+	client := newCachedClientWithClock(mockClient, func() time.Time { return clock })
+	ctx := context.Background()
+
+	// far more logouts than the cache holds entries
+	tokens := make([]string, 100)
+	for i := range tokens {
+		tokens[i] = "Bearer " + signHS256(t, []byte("secret"), map[string]any{"sub": "1", "jti": i, "exp": clock.Add(time.Hour).Unix()})
+
+		_, err := client.Logout(ctx, &authpb.LogoutRequest{}, tokens[i])
+		require.NoError(t, err)
+	}
+
+	for _, token := range tokens {
+		assert.True(t, client.Revoked(token))
+	}
+}
+
+func TestCachedAuthenticationClient_FailedLogoutDoesNotRevoke(t *testing.T) {
+	res := &authpb.VerifyTokenResponse{
+		Message: constant.MessageOK,
+		Data:    &authpb.VerifyTokenResponseData{User: dummyUser},
+	}
+
+	mockClient := new(MockAuthenticationServiceClient)
+	mockClient.On("VerifyToken", mock.Anything, mock.Anything).Return(res, nil).Once()
+	mockClient.On("Logout", mock.Anything, mock.Anything).Return(nil, status.Error(codes.Unavailable, "unavailable"))
+
+	client := newCachedClient(mockClient)
+	ctx := context.Background()
+
+	_, err := client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, "Bearer token")
+	require.NoError(t, err)
+
+	_, err = client.Logout(ctx, &authpb.LogoutRequest{}, "Bearer token")
+	require.Error(t, err)
+	assert.False(t, client.Revoked("Bearer token"))
+
+	// the cached response is still served
+	_, err = client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, "Bearer token")
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "VerifyToken", 1)
+}
+
+func TestCachedAuthenticationClient_ResponsesAreCopies(t *testing.T) {
+	res := &authpb.VerifyTokenResponse{
+		Message: constant.MessageOK,
+		Data:    &authpb.VerifyTokenResponseData{User: &authpb.User{Id: 1, Name: "user"}},
+	}
+
+	mockClient := new(MockAuthenticationServiceClient)
+	mockClient.On("VerifyToken", mock.Anything, mock.Anything).Return(res, nil).Once()
+
+	client := newCachedClient(mockClient)
+	ctx := context.Background()
+
+	first, err := client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, "Bearer token")
+	require.NoError(t, err)
+	first.Data.User.Name = "modified"
+
+	second, err := client.VerifyToken(ctx, &authpb.VerifyTokenRequest{}, "Bearer token")
+	require.NoError(t, err)
+	assert.Equal(t, "user", second.Data.User.Name)
+	assert.NotSame(t, first, second)
+}
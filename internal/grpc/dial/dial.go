@@ -0,0 +1,73 @@
+package dial
+
+import (
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/discovery"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/tlsconfig"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+)
+
+// Config is what the clients of the backend services share, see
+// config.GRPCAuthenticationClient and its siblings.
+type Config struct {
+	TLS            *config.TLS
+	Retry          *config.Retry
+	CircuitBreaker *config.CircuitBreaker
+	LoadBalancing  *config.LoadBalancing
+}
+
+// Options returns the dial options of the client of service. transport
+// replaces the TLS credentials and tracing when set, e.g. to dial an
+// in-memory listener in tests, the interceptors and endpoint discovery are
+// always added.
+func Options(service string, cfg Config, transport ...grpc.DialOption) ([]grpc.DialOption, error) {
+	opts := transport
+	if len(opts) == 0 {
+		creds, err := tlsconfig.ClientCredentials(cfg.TLS)
+		if err != nil {
+			logger.Error().Str("service", service).Err(err).Msg("Invalid gRPC client TLS config")
+			return nil, err
+		}
+		opts = []grpc.DialOption{
+			grpc.WithTransportCredentials(creds),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+				otelgrpc.WithTracerProvider(otel.GetTracerProvider()),
+				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
+			)),
+		}
+	}
+
+	interceptors := []grpc.UnaryClientInterceptor{
+		interceptor.RequestIDUnaryClientInterceptor(),
+		interceptor.LocaleUnaryClientInterceptor(),
+		interceptor.MetricsUnaryClientInterceptor(service),
+		interceptor.LoggingUnaryClientInterceptor(service),
+	}
+	// the breaker wraps the retries so it counts a call once, by its final
+	// outcome, and an open breaker rejects the call before any attempt
+	if cfg.CircuitBreaker != nil && cfg.CircuitBreaker.Enabled {
+		breaker := interceptor.NewCircuitBreaker(service, cfg.CircuitBreaker)
+		interceptors = append(interceptors, interceptor.CircuitBreakerUnaryClientInterceptor(breaker))
+	}
+	if cfg.Retry != nil && cfg.Retry.Enabled {
+		policy, err := interceptor.NewRetryPolicy(cfg.Retry)
+		if err != nil {
+			logger.Error().Str("service", service).Err(err).Msg("Invalid gRPC client retry policy")
+			return nil, err
+		}
+		interceptors = append(interceptors, interceptor.RetryUnaryClientInterceptor(service, policy))
+	}
+	opts = append(opts, grpc.WithChainUnaryInterceptor(interceptors...))
+
+	balancing, err := discovery.DialOptions(cfg.LoadBalancing)
+	if err != nil {
+		logger.Error().Str("service", service).Err(err).Msg("Invalid gRPC client load balancing config")
+		return nil, err
+	}
+
+	return append(opts, balancing...), nil
+}
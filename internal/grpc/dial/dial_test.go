@@ -0,0 +1,149 @@
+package dial_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/circuitbreaker"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/dial"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         dial.Config
+		expectedErr string
+	}{
+		{name: "defaults"},
+		{
+			name: "everything enabled",
+			cfg: dial.Config{
+				TLS:            &config.TLS{},
+				Retry:          &config.Retry{Enabled: true, MaxAttempts: 3, RetryableCodes: []string{"UNAVAILABLE"}},
+				CircuitBreaker: &config.CircuitBreaker{Enabled: true, ConsecutiveFailures: 5},
+				LoadBalancing:  &config.LoadBalancing{Policy: constant.LoadBalancingRoundRobin},
+			},
+		},
+		{
+			name:        "missing ca file",
+			cfg:         dial.Config{TLS: &config.TLS{Enabled: true, CAFile: filepath.Join(t.TempDir(), "ca.pem")}},
+			expectedErr: "ca.pem",
+		},
+		{
+			name:        "invalid retryable code",
+			cfg:         dial.Config{Retry: &config.Retry{Enabled: true, MaxAttempts: 3, RetryableCodes: []string{"SOMETIMES"}}},
+			expectedErr: `invalid retryable gRPC code "SOMETIMES"`,
+		},
+		{
+			name:        "unknown load balancing policy",
+			cfg:         dial.Config{LoadBalancing: &config.LoadBalancing{Policy: "random"}},
+			expectedErr: `unknown load balancing policy "random"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := dial.Options(constant.ServiceUpload, tt.cfg)
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, opts)
+		})
+	}
+}
+
+func TestOptions_Transport(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	// the in-memory transport replaces the default credentials
+	opts, err := dial.Options(constant.ServiceUpload, dial.Config{},
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	res, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+}
+
+// unavailableHealthServer fails every check, counting them.
+type unavailableHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	checks atomic.Int32
+}
+
+func (s *unavailableHealthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.checks.Add(1)
+	return nil, status.Error(codes.Unavailable, "unavailable")
+}
+
+func TestOptions_BreakerWrapsRetries(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	backend := &unavailableHealthServer{}
+	healthpb.RegisterHealthServer(srv, backend)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	opts, err := dial.Options("breaker-wraps-retries", dial.Config{
+		Retry: &config.Retry{
+			Enabled:            true,
+			MaxAttempts:        3,
+			InitialBackoff:     time.Millisecond,
+			MaxBackoff:         time.Millisecond,
+			Multiplier:         1,
+			RetryableCodes:     []string{"UNAVAILABLE"},
+			BudgetMinPerSecond: 10,
+		},
+		CircuitBreaker: &config.CircuitBreaker{Enabled: true, ConsecutiveFailures: 3, OpenTimeout: time.Minute},
+	},
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	client := healthpb.NewHealthClient(conn)
+	// the attempts of a retried call count as one failure, so the third call
+	// still reaches the backend and only then the breaker opens
+	for range 3 {
+		_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}, interceptor.Retryable())
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+	assert.Equal(t, int32(9), backend.checks.Load())
+
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{}, interceptor.Retryable())
+	assert.ErrorIs(t, err, circuitbreaker.ErrOpen)
+	assert.Equal(t, int32(9), backend.checks.Load())
+}
@@ -0,0 +1,71 @@
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer/leastrequest"
+	"google.golang.org/grpc/balancer/pickfirst"
+	"google.golang.org/grpc/balancer/roundrobin"
+
+	// registers the client side grpc_health_v1 checks used by healthCheckConfig
+	_ "google.golang.org/grpc/health"
+)
+
+var policies = map[string]string{
+	constant.LoadBalancingPickFirst:    pickfirst.Name,
+	constant.LoadBalancingRoundRobin:   roundrobin.Name,
+	constant.LoadBalancingLeastRequest: leastrequest.Name,
+}
+
+// DialOptions lets a client URL name several endpoints and balances calls
+// over them. Besides the resolvers built into gRPC, e.g. dns:///host:port,
+// URLs can be static:///host1:port,host2:port, file:///path/to/endpoints or
+// srv:///_grpc._tcp.service.example.com.
+func DialOptions(cfg *config.LoadBalancing) ([]grpc.DialOption, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	serviceConfig, err := ServiceConfig(cfg.Policy, cfg.HealthCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.DialOption{
+		grpc.WithResolvers(
+			&staticBuilder{},
+			&pollingBuilder{scheme: SchemeFile, interval: cfg.PollInterval, lookup: lookupFile, watch: watchFile},
+			&pollingBuilder{scheme: SchemeSRV, interval: cfg.PollInterval, lookup: lookupSRV},
+		),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+	}, nil
+}
+
+// ServiceConfig returns the gRPC service config selecting policy, with
+// healthCheck every endpoint's grpc_health_v1 status is watched and endpoints
+// that are not serving are ejected until they recover.
+func ServiceConfig(policy string, healthCheck bool) (string, error) {
+	name, ok := policies[policy]
+	if !ok {
+		return "", fmt.Errorf("unknown load balancing policy %q", policy)
+	}
+
+	sc := map[string]any{
+		"loadBalancingConfig": []map[string]any{{name: map[string]any{}}},
+	}
+	if healthCheck {
+		// an empty service name checks the overall health of the server
+		sc["healthCheckConfig"] = map[string]any{"serviceName": ""}
+	}
+
+	b, err := json.Marshal(sc)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,191 @@
+package discovery_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+// startBackend serves grpc_health_v1 on a random local port.
+func startBackend(t *testing.T) (string, *health.Server) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String(), hs
+}
+
+func dial(t *testing.T, target string, cfg *config.LoadBalancing) healthpb.HealthClient {
+	t.Helper()
+
+	opts, err := discovery.DialOptions(cfg)
+	require.NoError(t, err)
+
+	conn, err := grpc.NewClient(target, append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+// peers makes n calls and counts them by the endpoint that served them.
+func peers(t *testing.T, client healthpb.HealthClient, n int) map[string]int {
+	t.Helper()
+
+	counts := map[string]int{}
+	for range n {
+		var p peer.Peer
+		_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.Peer(&p), grpc.WaitForReady(true))
+		require.NoError(t, err)
+		counts[p.Addr.String()]++
+	}
+
+	return counts
+}
+
+func TestServiceConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		healthCheck bool
+		expected    string
+		expectErr   bool
+	}{
+		{
+			name:     "round robin",
+			policy:   constant.LoadBalancingRoundRobin,
+			expected: `{"loadBalancingConfig":[{"round_robin":{}}]}`,
+		},
+		{
+			name:        "least request with health checks",
+			policy:      constant.LoadBalancingLeastRequest,
+			healthCheck: true,
+			expected:    `{"healthCheckConfig":{"serviceName":""},"loadBalancingConfig":[{"least_request_experimental":{}}]}`,
+		},
+		{
+			name:     "pick first",
+			policy:   constant.LoadBalancingPickFirst,
+			expected: `{"loadBalancingConfig":[{"pick_first":{}}]}`,
+		},
+		{
+			name:      "unknown policy",
+			policy:    "random",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := discovery.ServiceConfig(tt.policy, tt.healthCheck)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, sc)
+		})
+	}
+}
+
+func TestDialOptions_StaticRoundRobin(t *testing.T) {
+	first, _ := startBackend(t)
+	second, _ := startBackend(t)
+
+	client := dial(t, "static:///"+first+","+second, &config.LoadBalancing{Policy: constant.LoadBalancingRoundRobin})
+
+	// wait for both endpoints to be connected
+	require.Eventually(t, func() bool { return len(peers(t, client, 2)) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	counts := peers(t, client, 10)
+	assert.Equal(t, 5, counts[first])
+	assert.Equal(t, 5, counts[second])
+}
+
+func TestDialOptions_HealthCheckEjectsEndpoints(t *testing.T) {
+	healthy, _ := startBackend(t)
+	unhealthy, hs := startBackend(t)
+
+	client := dial(t, "static:///"+healthy+","+unhealthy, &config.LoadBalancing{Policy: constant.LoadBalancingRoundRobin, HealthCheck: true})
+	require.Eventually(t, func() bool { return len(peers(t, client, 2)) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	require.Eventually(t, func() bool {
+		return peers(t, client, 4)[unhealthy] == 0
+	}, 5*time.Second, 10*time.Millisecond, "unhealthy endpoint should be ejected")
+
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	require.Eventually(t, func() bool {
+		return peers(t, client, 4)[unhealthy] > 0
+	}, 5*time.Second, 10*time.Millisecond, "recovered endpoint should get calls again")
+}
+
+func TestDialOptions_File(t *testing.T) {
+	tests := []struct {
+		name         string
+		pollInterval time.Duration
+		rename       bool
+		watched      bool
+	}{
+		{name: "polled", pollInterval: 10 * time.Millisecond},
+		// with an hour between polls only the watch can pick the change up
+		{name: "written in place", pollInterval: time.Hour, watched: true},
+		{name: "renamed over", pollInterval: time.Hour, rename: true, watched: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.watched && runtime.GOOS != "linux" {
+				t.Skip("files are only watched on linux")
+			}
+
+			first, _ := startBackend(t)
+			second, _ := startBackend(t)
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "endpoints")
+			require.NoError(t, os.WriteFile(path, []byte("# upload service\n"+first+"\n"), 0o644))
+
+			client := dial(t, "file://"+path, &config.LoadBalancing{Policy: constant.LoadBalancingRoundRobin, PollInterval: tt.pollInterval})
+			assert.Equal(t, map[string]int{first: 3}, peers(t, client, 3))
+
+			if tt.rename {
+				tmp := filepath.Join(dir, "endpoints.tmp")
+				require.NoError(t, os.WriteFile(tmp, []byte(second+"\n"), 0o644))
+				require.NoError(t, os.Rename(tmp, path))
+			} else {
+				require.NoError(t, os.WriteFile(path, []byte(second+"\n"), 0o644))
+			}
+
+			require.Eventually(t, func() bool {
+				counts := peers(t, client, 3)
+				return counts[second] == 3
+			}, 5*time.Second, 10*time.Millisecond, "endpoints should follow the file")
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	SchemeStatic = "static"
+	SchemeFile   = "file"
+	SchemeSRV    = "srv"
+)
+
+const defaultPollInterval = 10 * time.Second
+
+// staticBuilder resolves static:///host1:port,host2:port to the listed
+// endpoints.
+type staticBuilder struct{}
+
+func (b *staticBuilder) Scheme() string {
+	return SchemeStatic
+}
+
+func (b *staticBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	addrs := parseAddresses(strings.Split(target.Endpoint(), ","))
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("static target %q has no endpoints", target)
+	}
+
+	if err := cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+		return nil, err
+	}
+
+	return nopResolver{}, nil
+}
+
+type nopResolver struct{}
+
+func (nopResolver) ResolveNow(resolver.ResolveNowOptions) {}
+func (nopResolver) Close()                                {}
+
+type lookupFunc func(ctx context.Context, target resolver.Target) ([]resolver.Address, error)
+
+// watchFunc calls changed whenever the endpoints of target may have changed,
+// until ctx is done.
+type watchFunc func(ctx context.Context, target resolver.Target, changed func()) error
+
+// pollingBuilder resolves targets whose endpoints can change, e.g. a file or
+// SRV records, by looking them up again every interval. Targets that can be
+// watched are also looked up as soon as watch reports a change, polling then
+// only covers changes the watch missed or a watch that failed.
+type pollingBuilder struct {
+	scheme   string
+	interval time.Duration
+	lookup   lookupFunc
+	watch    watchFunc
+}
+
+func (b *pollingBuilder) Scheme() string {
+	return b.scheme
+}
+
+func (b *pollingBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	interval := b.interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &pollingResolver{
+		target:     target,
+		cc:         cc,
+		lookup:     b.lookup,
+		interval:   interval,
+		resolveNow: make(chan struct{}, 1),
+		cancel:     cancel,
+	}
+
+	r.wg.Add(1)
+	go r.run(ctx)
+
+	if b.watch != nil {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+
+			if err := b.watch(ctx, target, r.changed); err != nil {
+				logger.Warn().Str("target", target.String()).Err(err).Msg("Watching gRPC endpoints failed, falling back to polling")
+			}
+		}()
+	}
+
+	return r, nil
+}
+
+type pollingResolver struct {
+	target     resolver.Target
+	cc         resolver.ClientConn
+	lookup     lookupFunc
+	interval   time.Duration
+	resolveNow chan struct{}
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func (r *pollingResolver) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var last []string
+	for {
+		addrs, err := r.lookup(ctx, r.target)
+		if err == nil && len(addrs) == 0 {
+			err = errors.New("no endpoints")
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn().Str("target", r.target.String()).Err(err).Msg("gRPC endpoint discovery failed")
+			r.cc.ReportError(err)
+		} else if current := addressStrings(addrs); !slices.Equal(current, last) {
+			logger.Info().Str("target", r.target.String()).Strs("endpoints", current).Msg("gRPC endpoints discovered")
+			if err := r.cc.UpdateState(resolver.State{Addresses: addrs}); err == nil {
+				last = current
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.resolveNow:
+		}
+	}
+}
+
+// ResolveNow is called by gRPC when connections fail, it looks the endpoints
+// up again without waiting for the next interval.
+func (r *pollingResolver) ResolveNow(resolver.ResolveNowOptions) {
+	r.changed()
+}
+
+// changed wakes up run, changes arriving while a lookup is pending are
+// folded into it.
+func (r *pollingResolver) changed() {
+	select {
+	case r.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+func (r *pollingResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// lookupFile reads file:///path, one host:port per line. Blank lines and
+// lines starting with # are ignored. The file is watched by watchFile.
+func lookupFile(ctx context.Context, target resolver.Target) ([]resolver.Address, error) {
+	b, err := os.ReadFile(target.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return parseAddresses(lines), scanner.Err()
+}
+
+// lookupSRV resolves srv:///name to the targets of its SRV records, e.g.
+// srv:///_grpc._tcp.upload.default.svc.cluster.local.
+func lookupSRV(ctx context.Context, target resolver.Target) ([]resolver.Address, error) {
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", target.Endpoint())
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]resolver.Address, 0, len(records))
+	for _, srv := range records {
+		host := strings.TrimSuffix(srv.Target, ".")
+		addrs = append(addrs, resolver.Address{Addr: net.JoinHostPort(host, strconv.Itoa(int(srv.Port)))})
+	}
+
+	return addrs, nil
+}
+
+func parseAddresses(values []string) []resolver.Address {
+	addrs := []resolver.Address{}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			addrs = append(addrs, resolver.Address{Addr: v})
+		}
+	}
+
+	return addrs
+}
+
+func addressStrings(addrs []resolver.Address) []string {
+	s := make([]string, len(addrs))
+	for i, a := range addrs {
+		s[i] = a.Addr
+	}
+	slices.Sort(s)
+
+	return s
+}
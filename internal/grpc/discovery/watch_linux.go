@@ -0,0 +1,48 @@
+//go:build linux
+
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/resolver"
+)
+
+// watchFile calls changed whenever the file of a file:/// target is written
+// or replaced, until ctx is done. The directory is watched rather than the
+// file so a rename over it, e.g. a Kubernetes ConfigMap update swapping its
+// symlinks, is seen as well.
+func watchFile(ctx context.Context, target resolver.Target, changed func()) error {
+	fd, err := unix.InotifyInit1(unix.IN_NONBLOCK | unix.IN_CLOEXEC)
+	if err != nil {
+		return os.NewSyscallError("inotify_init1", err)
+	}
+	// a non blocking fd is served by the runtime poller, so closing the file
+	// unblocks the read below
+	f := os.NewFile(uintptr(fd), "inotify")
+	defer f.Close()
+
+	mask := uint32(unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_MOVED_TO)
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(target.URL.Path), mask); err != nil {
+		return os.NewSyscallError("inotify_add_watch", err)
+	}
+
+	stop := context.AfterFunc(ctx, func() { f.Close() })
+	defer stop()
+
+	// the events themselves are not needed, other files in the directory
+	// only cause a lookup that finds the endpoints unchanged
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		if _, err := f.Read(buf); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		changed()
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"google.golang.org/grpc/resolver"
+)
+
+func watchFile(ctx context.Context, target resolver.Target, changed func()) error {
+	return fmt.Errorf("watching files is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,53 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/circuitbreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BreakerFailureCodes are the codes that count as a failing backend, other
+// codes (e.g. NotFound, InvalidArgument) are regular responses.
+var BreakerFailureCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+	codes.Internal:          true,
+	codes.Unknown:           true,
+}
+
+// BreakerOpenError is returned without calling the backend while the breaker
+// is open. It converts to an Unavailable status and matches circuitbreaker.ErrOpen.
+type BreakerOpenError struct {
+	Service string
+}
+
+func (e *BreakerOpenError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Service, circuitbreaker.ErrOpen)
+}
+
+func (e *BreakerOpenError) Unwrap() error {
+	return circuitbreaker.ErrOpen
+}
+
+func (e *BreakerOpenError) GRPCStatus() *status.Status {
+	return status.New(codes.Unavailable, e.Error())
+}
+
+func CircuitBreakerUnaryClientInterceptor(breaker *circuitbreaker.Breaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		done, err := breaker.Allow()
+		if err != nil {
+			return &BreakerOpenError{Service: breaker.Name()}
+		}
+
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		done(!BreakerFailureCodes[status.Code(err)])
+
+		return err
+	}
+}
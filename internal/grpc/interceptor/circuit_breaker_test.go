@@ -0,0 +1,88 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/circuitbreaker"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func invokerReturning(err error, calls *int) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		return err
+	}
+}
+
+func TestCircuitBreakerUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		expectTrips bool
+	}{
+		{
+			name:        "unavailable trips the breaker",
+			err:         status.Error(codes.Unavailable, "connection refused"),
+			expectTrips: true,
+		},
+		{
+			name:        "deadline exceeded trips the breaker",
+			err:         status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			expectTrips: true,
+		},
+		{
+			name:        "application errors do not trip the breaker",
+			err:         status.Error(codes.NotFound, "not found"),
+			expectTrips: false,
+		},
+		{
+			name:        "success does not trip the breaker",
+			err:         nil,
+			expectTrips: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := interceptor.NewCircuitBreaker("test", &config.CircuitBreaker{
+				ConsecutiveFailures: 2,
+				OpenTimeout:         time.Minute,
+			})
+			i := interceptor.CircuitBreakerUnaryClientInterceptor(breaker)
+
+			calls := 0
+			invoker := invokerReturning(tt.err, &calls)
+
+			for range 2 {
+				err := i(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+				assert.Equal(t, tt.err, err)
+			}
+
+			err := i(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+
+			if !tt.expectTrips {
+				assert.Equal(t, 3, calls)
+				assert.Equal(t, circuitbreaker.StateClosed, breaker.State())
+				return
+			}
+
+			assert.Equal(t, 2, calls, "backend must not be called while open")
+			assert.Equal(t, circuitbreaker.StateOpen, breaker.State())
+			assert.True(t, errors.Is(err, circuitbreaker.ErrOpen))
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+
+			code, _ := helper.PrepareResponseFromGRPCError(err, nil)
+			assert.Equal(t, http.StatusServiceUnavailable, code)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package interceptor
+
+import (
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/circuitbreaker"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+)
+
+// NewCircuitBreaker creates the breaker for a downstream service and keeps
+// its state gauge up to date.
+func NewCircuitBreaker(service string, cfg *config.CircuitBreaker) *circuitbreaker.Breaker {
+	prometheus.CircuitBreakerState.WithLabelValues(service).Set(float64(circuitbreaker.StateClosed))
+
+	return circuitbreaker.New(service, circuitbreaker.Settings{
+		ConsecutiveFailures: cfg.ConsecutiveFailures,
+		FailureRate:         cfg.FailureRate,
+		MinRequests:         cfg.MinRequests,
+		Window:              cfg.Window,
+		OpenTimeout:         cfg.OpenTimeout,
+		HalfOpenRequests:    cfg.HalfOpenRequests,
+		OnStateChange: func(name string, from, to circuitbreaker.State) {
+			logger.Warn().
+				Str("service", name).
+				Str("from", from.String()).
+				Str("to", to.String()).
+				Msg("gRPC circuit breaker changed state")
+			prometheus.CircuitBreakerState.WithLabelValues(name).Set(float64(to))
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// LocaleUnaryClientInterceptor forwards the locale negotiated for the HTTP
+// request so backends can localize their own messages.
+func LocaleUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if i18n.Negotiated(ctx) {
+			ctx = metadata.AppendToOutgoingContext(ctx, constant.GRPCHeaderAcceptLanguage, i18n.FromContext(ctx))
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,58 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLocaleUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		expectedMD metadata.MD
+	}{
+		{
+			name:       "forwards the negotiated locale",
+			ctx:        i18n.NewContext(context.Background(), "es"),
+			expectedMD: metadata.Pairs(constant.GRPCHeaderAcceptLanguage, "es"),
+		},
+		{
+			name: "keeps metadata set by the client",
+			ctx: metadata.NewOutgoingContext(
+				i18n.NewContext(context.Background(), "fr"),
+				metadata.Pairs(constant.GRPCHeaderUserId, "1"),
+			),
+			expectedMD: metadata.Pairs(
+				constant.GRPCHeaderUserId, "1",
+				constant.GRPCHeaderAcceptLanguage, "fr",
+			),
+		},
+		{
+			name:       "no locale outside of a request",
+			ctx:        context.Background(),
+			expectedMD: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got metadata.MD
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				got, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			}
+
+			err := interceptor.LocaleUnaryClientInterceptor()(tt.ctx, "/test.Service/Method", nil, nil, nil, invoker)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMD, got)
+		})
+	}
+}
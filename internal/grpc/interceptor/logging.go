@@ -0,0 +1,33 @@
+package interceptor
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// LoggingUnaryClientInterceptor logs failed calls with the service, RPC method
+// and gRPC code as separate fields. Placed before the retry interceptor it
+// logs the outcome of the call instead of every attempt.
+func LoggingUnaryClientInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			logger.Ctx(ctx).Error().
+				Str("service", service).
+				Str("method", path.Base(method)).
+				Str("code", status.Code(err).String()).
+				Dur("latency", time.Since(start)).
+				Err(err).
+				Msg("gRPC call failed")
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,65 @@
+package interceptor_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoggingUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedFields []string
+	}{
+		{
+			name: "logs failed calls",
+			err:  status.Error(codes.NotFound, "video not found"),
+			expectedFields: []string{
+				`"level":"error"`,
+				`"service":"video_catalog"`,
+				`"method":"FindById"`,
+				`"code":"NotFound"`,
+				`"request_id":"req-1"`,
+				`"error":"rpc error: code = NotFound desc = video not found"`,
+			},
+		},
+		{
+			name:           "does not log successful calls",
+			err:            nil,
+			expectedFields: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, logger.Init(logger.Options{Format: logger.FormatJSON, Output: &buf}))
+			t.Cleanup(logger.InitConsole)
+
+			calls := 0
+			ctx := requestid.NewContext(context.Background(), "req-1")
+			err := interceptor.LoggingUnaryClientInterceptor("video_catalog")(
+				ctx, "/video_catalog.VideoCatalogService/FindById", nil, nil, nil, invokerReturning(tt.err, &calls),
+			)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, 1, calls)
+
+			if tt.expectedFields == nil {
+				assert.Empty(t, buf.String())
+			}
+			for _, field := range tt.expectedFields {
+				assert.Contains(t, buf.String(), field)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package interceptor
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+var connStates = []connectivity.State{
+	connectivity.Idle,
+	connectivity.Connecting,
+	connectivity.Ready,
+	connectivity.TransientFailure,
+	connectivity.Shutdown,
+}
+
+// MetricsUnaryClientInterceptor records call counts by status code, latency
+// and in-flight calls per service and method. Placed before the retry
+// interceptor it measures calls as the gateway sees them, retries included.
+func MetricsUnaryClientInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		name := path.Base(method)
+
+		inFlight := prometheus.GRPCClientInFlightRequests.WithLabelValues(service, name)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		prometheus.GRPCClientRequestDuration.WithLabelValues(service, name).Observe(time.Since(start).Seconds())
+		prometheus.GRPCClientRequests.WithLabelValues(service, name, status.Code(err).String()).Inc()
+
+		return err
+	}
+}
+
+// ConnStateSource is the part of *grpc.ClientConn WatchConnState needs.
+type ConnStateSource interface {
+	GetState() connectivity.State
+	WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool
+}
+
+// WatchConnState keeps the connection state gauge of service up to date until
+// ctx is done or the connection is closed.
+func WatchConnState(ctx context.Context, service string, conn ConnStateSource) {
+	state := conn.GetState()
+	setConnState(service, state)
+
+	for state != connectivity.Shutdown {
+		if !conn.WaitForStateChange(ctx, state) {
+			return
+		}
+
+		state = conn.GetState()
+		prometheus.GRPCClientConnectionStateChanges.WithLabelValues(service, state.String()).Inc()
+		setConnState(service, state)
+	}
+}
+
+func setConnState(service string, current connectivity.State) {
+	for _, s := range connStates {
+		v := 0.0
+		if s == current {
+			v = 1
+		}
+		prometheus.GRPCClientConnectionState.WithLabelValues(service, s.String()).Set(v)
+	}
+}
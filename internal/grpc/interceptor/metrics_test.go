@@ -0,0 +1,114 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	prometheuslib "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+func histogramCount(t *testing.T, o prometheuslib.Observer) uint64 {
+	t.Helper()
+
+	m := &dto.Metric{}
+	require.NoError(t, o.(prometheuslib.Metric).Write(m))
+
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMetricsUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name         string
+		service      string
+		err          error
+		expectedCode string
+	}{
+		{
+			name:         "success",
+			service:      "metrics_ok",
+			err:          nil,
+			expectedCode: "OK",
+		},
+		{
+			name:         "failure is counted by code",
+			service:      "metrics_unavailable",
+			err:          status.Error(codes.Unavailable, "connection refused"),
+			expectedCode: "Unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight float64
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				inFlight = testutil.ToFloat64(prometheus.GRPCClientInFlightRequests.WithLabelValues(tt.service, "FindById"))
+				return tt.err
+			}
+
+			err := interceptor.MetricsUnaryClientInterceptor(tt.service)(
+				context.Background(), "/video_catalog.VideoCatalogService/FindById", nil, nil, nil, invoker,
+			)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, 1.0, inFlight, "call should be in flight while the invoker runs")
+			assert.Equal(t, 0.0, testutil.ToFloat64(prometheus.GRPCClientInFlightRequests.WithLabelValues(tt.service, "FindById")))
+			assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.GRPCClientRequests.WithLabelValues(tt.service, "FindById", tt.expectedCode)))
+			assert.EqualValues(t, 1, histogramCount(t, prometheus.GRPCClientRequestDuration.WithLabelValues(tt.service, "FindById")))
+		})
+	}
+}
+
+type fakeConn struct {
+	states []connectivity.State
+}
+
+func (f *fakeConn) GetState() connectivity.State {
+	return f.states[0]
+}
+
+func (f *fakeConn) WaitForStateChange(ctx context.Context, s connectivity.State) bool {
+	if len(f.states) == 1 {
+		return false
+	}
+	f.states = f.states[1:]
+	return true
+}
+
+func TestWatchConnState(t *testing.T) {
+	conn := &fakeConn{states: []connectivity.State{
+		connectivity.Idle,
+		connectivity.Connecting,
+		connectivity.Ready,
+		connectivity.TransientFailure,
+		connectivity.Ready,
+		connectivity.Shutdown,
+	}}
+
+	interceptor.WatchConnState(context.Background(), "watched", conn)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.GRPCClientConnectionState.WithLabelValues("watched", "SHUTDOWN")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(prometheus.GRPCClientConnectionState.WithLabelValues("watched", "READY")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(prometheus.GRPCClientConnectionStateChanges.WithLabelValues("watched", "READY")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.GRPCClientConnectionStateChanges.WithLabelValues("watched", "TRANSIENT_FAILURE")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(prometheus.GRPCClientConnectionStateChanges.WithLabelValues("watched", "IDLE")))
+}
+
+func TestWatchConnState_StopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conn := &fakeConn{states: []connectivity.State{connectivity.Ready}}
+	interceptor.WatchConnState(ctx, "cancelled", conn)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.GRPCClientConnectionState.WithLabelValues("cancelled", "READY")))
+}
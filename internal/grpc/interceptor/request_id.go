@@ -0,0 +1,23 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDUnaryClientInterceptor forwards the request id of the incoming HTTP
+// request to the backend. It runs when the call is made, so it keeps working
+// for clients that replace the outgoing metadata with NewOutgoingContext.
+func RequestIDUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id := requestid.FromContext(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, constant.GRPCHeaderRequestId, id)
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,58 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestIDUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		expectedMD metadata.MD
+	}{
+		{
+			name:       "forwards the request id",
+			ctx:        requestid.NewContext(context.Background(), "req-1"),
+			expectedMD: metadata.Pairs(constant.GRPCHeaderRequestId, "req-1"),
+		},
+		{
+			name: "keeps metadata set by the client",
+			ctx: metadata.NewOutgoingContext(
+				requestid.NewContext(context.Background(), "req-1"),
+				metadata.Pairs(constant.GRPCHeaderAuthorization, "Bearer token"),
+			),
+			expectedMD: metadata.Pairs(
+				constant.GRPCHeaderAuthorization, "Bearer token",
+				constant.GRPCHeaderRequestId, "req-1",
+			),
+		},
+		{
+			name:       "no request id outside of a request",
+			ctx:        context.Background(),
+			expectedMD: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got metadata.MD
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				got, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			}
+
+			err := interceptor.RequestIDUnaryClientInterceptor()(tt.ctx, "/test.Service/Method", nil, nil, nil, invoker)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMD, got)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/circuitbreaker"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type retryCallOption struct {
+	grpc.EmptyCallOption
+	hedge bool
+}
+
+// Retryable marks an idempotent call that may be sent again after a
+// retryable failure. Health checks are not marked, the prober checks again on
+// its next tick anyway.
+func Retryable() grpc.CallOption {
+	return retryCallOption{}
+}
+
+// Hedgeable marks a read that may additionally be sent concurrently when the
+// first attempt is slow. It implies Retryable.
+func Hedgeable() grpc.CallOption {
+	return retryCallOption{hedge: true}
+}
+
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	RetryableCodes map[codes.Code]bool
+	HedgingDelay   time.Duration
+	// Budget is shared by the calls of the client, nil leaves retries
+	// unbounded across calls.
+	Budget *RetryBudget
+}
+
+func NewRetryPolicy(cfg *config.Retry) (*RetryPolicy, error) {
+	retryableCodes := map[codes.Code]bool{}
+	for _, name := range cfg.RetryableCodes {
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(`"` + strings.ToUpper(strings.TrimSpace(name)) + `"`)); err != nil {
+			return nil, fmt.Errorf("invalid retryable gRPC code %q", name)
+		}
+		retryableCodes[code] = true
+	}
+
+	return &RetryPolicy{
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialBackoff: cfg.InitialBackoff,
+		MaxBackoff:     cfg.MaxBackoff,
+		Multiplier:     cfg.Multiplier,
+		Jitter:         cfg.Jitter,
+		RetryableCodes: retryableCodes,
+		HedgingDelay:   cfg.HedgingDelay,
+		Budget:         NewRetryBudget(cfg.BudgetRatio, cfg.BudgetMinPerSecond),
+	}, nil
+}
+
+func RetryUnaryClientInterceptor(service string, policy *RetryPolicy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		retryable, hedgeable := retryOptions(opts)
+		if !retryable || policy.MaxAttempts <= 1 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		if policy.Budget != nil {
+			policy.Budget.Deposit()
+		}
+
+		if msg, ok := reply.(proto.Message); ok && hedgeable && policy.HedgingDelay > 0 {
+			return policy.hedge(ctx, service, method, req, msg, cc, invoker, opts)
+		}
+
+		return policy.retry(ctx, service, method, req, reply, cc, invoker, opts)
+	}
+}
+
+func (p *RetryPolicy) retry(ctx context.Context, service, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts []grpc.CallOption) error {
+	for attempt := 1; ; attempt++ {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if attempt >= p.MaxAttempts || !p.shouldRetry(err) {
+			return err
+		}
+
+		backoff := p.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			return err
+		}
+		if !p.withdraw(service, method) {
+			return err
+		}
+
+		prometheus.GRPCClientRetries.WithLabelValues(service, method, status.Code(err).String()).Inc()
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+type hedgeResult struct {
+	reply proto.Message
+	err   error
+}
+
+// hedge sends a new attempt every HedgingDelay, or right away after a
+// retryable failure, until one succeeds or MaxAttempts are in flight. The
+// first successful reply wins and the remaining attempts are cancelled.
+func (p *RetryPolicy) hedge(ctx context.Context, service, method string, req any, reply proto.Message, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts []grpc.CallOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, p.MaxAttempts)
+	sent, pending := 0, 0
+	send := func() {
+		r := reply.ProtoReflect().New().Interface()
+		sent++
+		pending++
+		go func() {
+			results <- hedgeResult{reply: r, err: invoker(ctx, method, req, r, cc, opts...)}
+		}()
+	}
+
+	send()
+	timer := time.NewTimer(p.HedgingDelay)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case <-timer.C:
+			// without budget the call stops hedging and waits for the
+			// attempts in flight
+			if sent < p.MaxAttempts && ctx.Err() == nil && p.withdraw(service, method) {
+				prometheus.GRPCClientHedgedRequests.WithLabelValues(service, method).Inc()
+				send()
+				timer.Reset(p.HedgingDelay)
+			}
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				proto.Merge(reply, res.reply)
+				return nil
+			}
+
+			lastErr = res.err
+			if !p.shouldRetry(res.err) {
+				return res.err
+			}
+
+			if sent < p.MaxAttempts && ctx.Err() == nil && p.withdraw(service, method) {
+				prometheus.GRPCClientRetries.WithLabelValues(service, method, status.Code(res.err).String()).Inc()
+				send()
+				timer.Reset(p.HedgingDelay)
+			} else if pending == 0 {
+				return lastErr
+			}
+		}
+	}
+}
+
+// withdraw takes a retry from the budget, retries it rejects are counted.
+func (p *RetryPolicy) withdraw(service, method string) bool {
+	if p.Budget == nil || p.Budget.Withdraw() {
+		return true
+	}
+
+	prometheus.GRPCClientRetriesThrottled.WithLabelValues(service, method).Inc()
+	return false
+}
+
+func (p *RetryPolicy) shouldRetry(err error) bool {
+	if err == nil || errors.Is(err, circuitbreaker.ErrOpen) {
+		return false
+	}
+
+	return p.RetryableCodes[status.Code(err)]
+}
+
+// backoff returns the exponential delay before the given retry with
+// +/- Jitter applied.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(retry-1))
+	backoff = math.Min(backoff, float64(p.MaxBackoff))
+
+	if p.Jitter > 0 {
+		backoff *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(backoff)
+}
+
+func retryOptions(opts []grpc.CallOption) (retryable bool, hedgeable bool) {
+	for _, o := range opts {
+		if r, ok := o.(retryCallOption); ok {
+			retryable = true
+			hedgeable = hedgeable || r.hedge
+		}
+	}
+
+	return retryable, hedgeable
+}
@@ -0,0 +1,74 @@
+package interceptor
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudgetWindow is how long unused budget accrues, at the minimum rate.
+const retryBudgetWindow = 10 * time.Second
+
+// RetryBudget bounds the retries of a client across all its calls, so a
+// backend that is failing gets at most Ratio more calls than it is sent
+// anyway, and not MaxAttempts times as many. It is a token bucket: every call
+// deposits Ratio tokens, the bucket refills at MinPerSecond tokens per second
+// on its own so calls are still retried under low traffic, and every retry or
+// hedged attempt withdraws one token.
+type RetryBudget struct {
+	mu           sync.Mutex
+	ratio        float64
+	minPerSecond float64
+	capacity     float64
+	tokens       float64
+	last         time.Time
+	now          func() time.Time
+}
+
+func NewRetryBudget(ratio float64, minPerSecond float64) *RetryBudget {
+	return NewRetryBudgetWithClock(ratio, minPerSecond, time.Now)
+}
+
+func NewRetryBudgetWithClock(ratio float64, minPerSecond float64, now func() time.Time) *RetryBudget {
+	ratio, minPerSecond = max(ratio, 0), max(minPerSecond, 0)
+	capacity := max(minPerSecond, 1) * retryBudgetWindow.Seconds()
+
+	return &RetryBudget{
+		ratio:        ratio,
+		minPerSecond: minPerSecond,
+		capacity:     capacity,
+		tokens:       capacity,
+		last:         now(),
+		now:          now,
+	}
+}
+
+// Deposit records a call that may be retried.
+func (b *RetryBudget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens = min(b.tokens+b.ratio, b.capacity)
+}
+
+// Withdraw reports whether a retry may be sent and takes its token.
+func (b *RetryBudget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+func (b *RetryBudget) refill() {
+	now := b.now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.tokens+elapsed.Seconds()*b.minPerSecond, b.capacity)
+	}
+	b.last = now
+}
@@ -0,0 +1,84 @@
+package interceptor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBudget(t *testing.T) {
+	tests := []struct {
+		name          string
+		ratio         float64
+		minPerSecond  float64
+		drain         bool
+		deposits      int
+		elapsed       time.Duration
+		expectRetries int
+	}{
+		{
+			name:          "starts with a full window",
+			minPerSecond:  1,
+			expectRetries: 10,
+		},
+		{
+			name:          "refills at the minimum rate",
+			minPerSecond:  1,
+			drain:         true,
+			elapsed:       3 * time.Second,
+			expectRetries: 3,
+		},
+		{
+			name:          "refills up to a full window",
+			minPerSecond:  2,
+			drain:         true,
+			elapsed:       time.Hour,
+			expectRetries: 20,
+		},
+		{
+			name:          "earns the ratio per call",
+			ratio:         0.5,
+			drain:         true,
+			deposits:      5,
+			expectRetries: 2,
+		},
+		{
+			name:          "does not refill without calls or a minimum rate",
+			drain:         true,
+			elapsed:       time.Hour,
+			expectRetries: 0,
+		},
+		{
+			name:          "treats a negative ratio as zero",
+			ratio:         -1,
+			drain:         true,
+			deposits:      5,
+			expectRetries: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(0, 0)
+			budget := interceptor.NewRetryBudgetWithClock(tt.ratio, tt.minPerSecond, func() time.Time { return now })
+
+			if tt.drain {
+				for budget.Withdraw() {
+				}
+			}
+			for range tt.deposits {
+				budget.Deposit()
+			}
+			now = now.Add(tt.elapsed)
+
+			retries := 0
+			for retries < 100 && budget.Withdraw() {
+				retries++
+			}
+
+			assert.Equal(t, tt.expectRetries, retries)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package interceptor_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func newRetryPolicy(t *testing.T, hedgingDelay time.Duration) *interceptor.RetryPolicy {
+	t.Helper()
+
+	policy, err := interceptor.NewRetryPolicy(&config.Retry{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableCodes: []string{"UNAVAILABLE", "resource_exhausted"},
+		HedgingDelay:   hedgingDelay,
+	})
+	require.NoError(t, err)
+
+	return policy
+}
+
+// sequenceInvoker returns the given errors in order and succeeds afterwards.
+func sequenceInvoker(errs []error, calls *int32) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		n := atomic.AddInt32(calls, 1)
+		if int(n) <= len(errs) {
+			return errs[n-1]
+		}
+		return nil
+	}
+}
+
+func TestRetryUnaryClientInterceptor(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	notFound := status.Error(codes.NotFound, "not found")
+
+	tests := []struct {
+		name        string
+		errs        []error
+		opts        []grpc.CallOption
+		timeout     time.Duration
+		expectCode  codes.Code
+		expectCalls int32
+	}{
+		{
+			name:        "retries retryable codes until success",
+			errs:        []error{unavailable, status.Error(codes.ResourceExhausted, "busy")},
+			opts:        []grpc.CallOption{interceptor.Retryable()},
+			expectCode:  codes.OK,
+			expectCalls: 3,
+		},
+		{
+			name:        "gives up after max attempts",
+			errs:        []error{unavailable, unavailable, unavailable, unavailable},
+			opts:        []grpc.CallOption{interceptor.Retryable()},
+			expectCode:  codes.Unavailable,
+			expectCalls: 3,
+		},
+		{
+			name:        "does not retry non retryable codes",
+			errs:        []error{notFound},
+			opts:        []grpc.CallOption{interceptor.Retryable()},
+			expectCode:  codes.NotFound,
+			expectCalls: 1,
+		},
+		{
+			name:        "does not retry calls that are not marked retryable",
+			errs:        []error{unavailable},
+			expectCode:  codes.Unavailable,
+			expectCalls: 1,
+		},
+		{
+			name:        "does not retry an open circuit breaker",
+			errs:        []error{&interceptor.BreakerOpenError{Service: "test"}},
+			opts:        []grpc.CallOption{interceptor.Retryable()},
+			expectCode:  codes.Unavailable,
+			expectCalls: 1,
+		},
+		{
+			name:        "stops when the deadline is shorter than the backoff",
+			errs:        []error{unavailable},
+			opts:        []grpc.CallOption{interceptor.Retryable()},
+			timeout:     time.Microsecond,
+			expectCode:  codes.Unavailable,
+			expectCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := interceptor.RetryUnaryClientInterceptor("test", newRetryPolicy(t, 0))
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			var calls int32
+			err := i(ctx, "/svc/Method", nil, nil, nil, sequenceInvoker(tt.errs, &calls), tt.opts...)
+
+			assert.Equal(t, tt.expectCode, status.Code(err))
+			assert.Equal(t, tt.expectCalls, calls)
+		})
+	}
+}
+
+func TestRetryUnaryClientInterceptor_Hedging(t *testing.T) {
+	i := interceptor.RetryUnaryClientInterceptor("test", newRetryPolicy(t, 5*time.Millisecond))
+
+	var calls int32
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// the first attempt hangs until it is cancelled by the winner
+			<-ctx.Done()
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		reply.(*healthpb.HealthCheckResponse).Status = healthpb.HealthCheckResponse_SERVING
+		return nil
+	}
+
+	reply := &healthpb.HealthCheckResponse{}
+	err := i(context.Background(), "/svc/Method", nil, reply, nil, invoker, interceptor.Hedgeable())
+
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, reply.Status)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestNewRetryPolicy_InvalidCode(t *testing.T) {
+	_, err := interceptor.NewRetryPolicy(&config.Retry{RetryableCodes: []string{"NOT_A_CODE"}})
+	assert.Error(t, err)
+}
+
+func TestRetryUnaryClientInterceptor_Budget(t *testing.T) {
+	policy := newRetryPolicy(t, 0)
+	// no refill and no earned retries, only the 10 retries the window starts with
+	policy.Budget = interceptor.NewRetryBudgetWithClock(0, 0, func() time.Time { return time.Unix(0, 0) })
+	i := interceptor.RetryUnaryClientInterceptor("budget", policy)
+
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	throttled := prometheus.GRPCClientRetriesThrottled.WithLabelValues("budget", "/svc/Method")
+	before := testutil.ToFloat64(throttled)
+
+	var calls int32
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		atomic.AddInt32(&calls, 1)
+		return unavailable
+	}
+	for range 6 {
+		err := i(context.Background(), "/svc/Method", nil, nil, nil, invoker, interceptor.Retryable())
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+
+	// 5 calls spend 2 retries each, the 6th is sent once
+	assert.Equal(t, int32(16), calls)
+	assert.Equal(t, 1.0, testutil.ToFloat64(throttled)-before)
+}
@@ -4,12 +4,11 @@ import (
 	"context"
 
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/dial"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 	uploadpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/upload/upload"
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
@@ -43,23 +42,24 @@ func NewClient(ctx context.Context, opt *InitClientOptions) (UploadService, *grp
 	if opt.Factory == nil {
 		opt.Factory = defaultFactory
 	}
-	if len(opt.DialOptions) == 0 {
-		opt.DialOptions = []grpc.DialOption{
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithStatsHandler(otelgrpc.NewClientHandler(
-				otelgrpc.WithTracerProvider(otel.GetTracerProvider()),
-				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
-			)),
-		}
+
+	opts, err := dial.Options(constant.ServiceUpload, dial.Config{
+		TLS:            opt.Config.TLS,
+		Retry:          opt.Config.Retry,
+		CircuitBreaker: opt.Config.CircuitBreaker,
+		LoadBalancing:  opt.Config.LoadBalancing,
+	}, opt.DialOptions...)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	conn, err := opt.Dial(opt.Config.URL, opt.DialOptions...)
+	conn, err := opt.Dial(opt.Config.URL, opts...)
 	if err != nil {
-		logger.Error("Upload gRPC client failed to connect on %q: %v", opt.Config.URL, err)
+		logger.Error().Str("service", constant.ServiceUpload).Str("url", opt.Config.URL).Err(err).Msg("gRPC client failed to connect")
 		return nil, nil, err
 	}
 
-	logger.Info("Upload gRPC client connected on %q", opt.Config.URL)
+	logger.Info().Str("service", constant.ServiceUpload).Str("url", opt.Config.URL).Msg("gRPC client connected")
 
 	uploadClient := opt.Factory(
 		uploadpb.NewUploadServiceClient(conn),
@@ -73,6 +73,6 @@ func NewClient(ctx context.Context, opt *InitClientOptions) (UploadService, *grp
 		}
 	}
 
-	logger.Info("Upload gRPC client ready on %q", opt.Config.URL)
+	logger.Info().Str("service", constant.ServiceUpload).Str("url", opt.Config.URL).Msg("gRPC client ready")
 	return uploadClient, conn, nil
 }
@@ -34,7 +34,6 @@ func (u *UploadClient) CreatePresignedUrl(ctx context.Context, in *uploadpb.Crea
 
 	response, err := u.client.CreatePresignedUrl(ctx, in)
 	if err != nil {
-		logger.Error("gRPC uploadClient.CreatePresignedUrl failed %v", err)
 		return nil, err
 	}
 
@@ -50,7 +49,6 @@ func (u *UploadClient) UploadedWebhook(ctx context.Context, in *uploadpb.Uploade
 
 	response, err := u.client.UploadedWebhook(ctx, in)
 	if err != nil {
-		logger.Error("gRPC uploadClient.CreatePresignedUrl failed %v", err)
 		return nil, err
 	}
 
@@ -63,12 +61,14 @@ func (u *UploadClient) Health(ctx context.Context) error {
 
 	res, err := u.health.Check(ctx, &healthpb.HealthCheckRequest{})
 	if err != nil {
-		logger.Error("Upload gRPC health check failed! %v", err)
 		return err
 	}
 
 	if res.Status == healthpb.HealthCheckResponse_NOT_SERVING {
-		logger.Error("Upload gRPC health check failed")
+		logger.Ctx(ctx).Error().
+			Str("service", constant.ServiceUpload).
+			Str("status", res.Status.String()).
+			Msg("gRPC health check failed")
 		return errors.New("upload grpc health check failed")
 	}
 
@@ -0,0 +1,123 @@
+package videocatalog
+
+import (
+	"context"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	videocatalogpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/video_catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/singleflight"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// CoalescingVideoCatalogClient shares one in-flight FindAll or FindById call
+// between concurrent callers asking for the same request, so a burst of reads
+// for a popular video reaches the video catalog service once.
+//
+// A shared call belongs to none of its callers: it is sent with a request id
+// of its own and traced under a span of its own, linked to the span of every
+// caller and linked from them. Logs and traces of the backend call are found
+// through those links, not under the request id of a caller.
+type CoalescingVideoCatalogClient struct {
+	VideoCatalogService
+	findAll  *singleflight.Group[string, *videocatalogpb.FindAllResponse]
+	findById *singleflight.Group[string, *videocatalogpb.FindByIdResponse]
+}
+
+func NewCoalescingVideoCatalogClient(next VideoCatalogService) *CoalescingVideoCatalogClient {
+	return &CoalescingVideoCatalogClient{
+		VideoCatalogService: next,
+		findAll:             newCoalescingGroup[*videocatalogpb.FindAllResponse]("FindAll"),
+		findById:            newCoalescingGroup[*videocatalogpb.FindByIdResponse]("FindById"),
+	}
+}
+
+func newCoalescingGroup[V any](rpc string) *singleflight.Group[string, V] {
+	g := singleflight.NewGroup[string, V]()
+	g.Detach = func(ctx context.Context) context.Context {
+		return detach(ctx, rpc)
+	}
+	g.Join = join
+
+	return g
+}
+
+func (v *CoalescingVideoCatalogClient) FindAll(ctx context.Context, in *videocatalogpb.FindAllRequest) (*videocatalogpb.FindAllResponse, error) {
+	key, err := requestKey(ctx, in)
+	if err != nil {
+		return v.VideoCatalogService.FindAll(ctx, in)
+	}
+
+	res, shared, err := v.findAll.Do(ctx, key, func(ctx context.Context) (*videocatalogpb.FindAllResponse, error) {
+		defer trace.SpanFromContext(ctx).End()
+		return v.VideoCatalogService.FindAll(ctx, in)
+	})
+	if shared {
+		prometheus.GRPCClientCoalescedRequests.WithLabelValues(constant.ServiceVideoCatalog, "FindAll").Inc()
+		res = clone(res)
+	}
+
+	return res, err
+}
+
+func (v *CoalescingVideoCatalogClient) FindById(ctx context.Context, in *videocatalogpb.FindByIdRequest) (*videocatalogpb.FindByIdResponse, error) {
+	key, err := requestKey(ctx, in)
+	if err != nil {
+		return v.VideoCatalogService.FindById(ctx, in)
+	}
+
+	res, shared, err := v.findById.Do(ctx, key, func(ctx context.Context) (*videocatalogpb.FindByIdResponse, error) {
+		defer trace.SpanFromContext(ctx).End()
+		return v.VideoCatalogService.FindById(ctx, in)
+	})
+	if shared {
+		prometheus.GRPCClientCoalescedRequests.WithLabelValues(constant.ServiceVideoCatalog, "FindById").Inc()
+		res = clone(res)
+	}
+
+	return res, err
+}
+
+// detach starts the context of a shared call with a new request id and root
+// span. Of the values of the caller starting it only the locale is kept, it
+// is part of the request key.
+func detach(ctx context.Context, rpc string) context.Context {
+	shared := requestid.NewContext(context.Background(), requestid.New())
+	if i18n.Negotiated(ctx) {
+		shared = i18n.NewContext(shared, i18n.FromContext(ctx))
+	}
+
+	shared, _ = otel.Tracer(constant.ServiceName).Start(shared, constant.ServiceVideoCatalog+"."+rpc+" coalesced",
+		trace.WithAttributes(attribute.String("request_id", requestid.FromContext(shared))),
+	)
+
+	return shared
+}
+
+// join links the spans of a shared call and of one of its callers both ways.
+func join(call context.Context, caller context.Context) {
+	trace.SpanFromContext(call).AddLink(trace.LinkFromContext(caller, attribute.String("request_id", requestid.FromContext(caller))))
+	trace.SpanFromContext(caller).AddLink(trace.LinkFromContext(call, attribute.String("request_id", requestid.FromContext(call))))
+}
+
+// requestKey identifies identical requests by their deterministic wire
+// encoding and locale, the backend localizes its response.
+func requestKey(ctx context.Context, in proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(in)
+	return i18n.FromContext(ctx) + " " + string(b), err
+}
+
+// clone gives every caller sharing a response its own copy, so one handler
+// modifying it cannot affect the others.
+func clone[T proto.Message](m T) T {
+	if !m.ProtoReflect().IsValid() {
+		return m
+	}
+
+	return proto.Clone(m).(T)
+}
@@ -0,0 +1,201 @@
+package videocatalog_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	videocatalog "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/video-catalog"
+	videocatalogpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/video_catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+func newCoalescingClient(mockClient *MockVideoCatalogServiceClient) *videocatalog.CoalescingVideoCatalogClient {
+	cfg := &config.GRPCVideoCatalogClient{Timeout: 2 * time.Second}
+	return videocatalog.NewCoalescingVideoCatalogClient(videocatalog.NewVideoCatalogClient(mockClient, nil, cfg))
+}
+
+func TestCoalescingVideoCatalogClient_FindById(t *testing.T) {
+	res := &videocatalogpb.FindByIdResponse{
+		Message: constant.MessageOK,
+		Data:    &videocatalogpb.FindByIdResponseData{Video: dummyVideo},
+	}
+
+	tests := []struct {
+		name          string
+		requests      []*videocatalogpb.FindByIdRequest
+		expectedCalls int
+	}{
+		{
+			name: "identical requests share one call",
+			requests: []*videocatalogpb.FindByIdRequest{
+				{Id: 1}, {Id: 1}, {Id: 1}, {Id: 1},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name: "different requests are not coalesced",
+			requests: []*videocatalogpb.FindByIdRequest{
+				{Id: 1}, {Id: 2},
+			},
+			expectedCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockVideoCatalogServiceClient)
+			mockClient.On("FindById", mock.Anything, mock.Anything).
+				After(50*time.Millisecond).
+				Return(res, nil)
+
+			client := newCoalescingClient(mockClient)
+
+			var wg sync.WaitGroup
+			for _, req := range tt.requests {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					got, err := client.FindById(context.Background(), req)
+					require.NoError(t, err)
+					assert.True(t, proto.Equal(res, got))
+				}()
+			}
+			wg.Wait()
+
+			mockClient.AssertNumberOfCalls(t, "FindById", tt.expectedCalls)
+		})
+	}
+}
+
+func TestCoalescingVideoCatalogClient_FindAll(t *testing.T) {
+	res := &videocatalogpb.FindAllResponse{
+		Message: constant.MessageOK,
+		Data: &videocatalogpb.FindAllResponseData{
+			Videos: []*videocatalogpb.Video{dummyVideo},
+		},
+	}
+
+	mockClient := new(MockVideoCatalogServiceClient)
+	mockClient.On("FindAll", mock.Anything, mock.Anything).
+		After(50*time.Millisecond).
+		Return(res, nil)
+
+	client := newCoalescingClient(mockClient)
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := client.FindAll(context.Background(), &videocatalogpb.FindAllRequest{Page: 2, Limit: 10})
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(res, got))
+		}()
+	}
+	wg.Wait()
+
+	mockClient.AssertNumberOfCalls(t, "FindAll", 1)
+}
+
+func TestCoalescingVideoCatalogClient_CancelledCallerDoesNotFailOthers(t *testing.T) {
+	res := &videocatalogpb.FindByIdResponse{
+		Message: constant.MessageOK,
+		Data:    &videocatalogpb.FindByIdResponseData{Video: dummyVideo},
+	}
+
+	mockClient := new(MockVideoCatalogServiceClient)
+	mockClient.On("FindById", mock.Anything, mock.Anything).
+		After(100*time.Millisecond).
+		Return(res, nil)
+
+	client := newCoalescingClient(mockClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	first := make(chan error)
+	go func() {
+		_, err := client.FindById(ctx, &videocatalogpb.FindByIdRequest{Id: 1})
+		first <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	got, err := client.FindById(context.Background(), &videocatalogpb.FindByIdRequest{Id: 1})
+
+	assert.ErrorIs(t, <-first, context.DeadlineExceeded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(res, got))
+	mockClient.AssertNumberOfCalls(t, "FindById", 1)
+}
+
+func TestCoalescingVideoCatalogClient_SharedCallTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var callCtx context.Context
+	mockClient := new(MockVideoCatalogServiceClient)
+	mockClient.On("FindById", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { callCtx = args.Get(0).(context.Context) }).
+		After(50*time.Millisecond).
+		Return(&videocatalogpb.FindByIdResponse{Message: constant.MessageOK}, nil)
+
+	client := newCoalescingClient(mockClient)
+
+	callers := map[string]trace.SpanContext{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range []string{"request-a", "request-b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, span := otel.Tracer("test").Start(requestid.NewContext(context.Background(), id), "caller")
+			defer span.End()
+
+			mu.Lock()
+			callers[id] = span.SpanContext()
+			mu.Unlock()
+
+			_, err := client.FindById(ctx, &videocatalogpb.FindByIdRequest{Id: 1})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	mockClient.AssertNumberOfCalls(t, "FindById", 1)
+	require.NotNil(t, callCtx)
+	assert.NotEmpty(t, requestid.FromContext(callCtx))
+	assert.NotContains(t, callers, requestid.FromContext(callCtx), "the call has a request id of its own")
+
+	callSpan := trace.SpanContextFromContext(callCtx)
+	for _, caller := range callers {
+		assert.NotEqual(t, caller.TraceID(), callSpan.TraceID(), "the call has a trace of its own")
+	}
+
+	linked := map[trace.SpanID]bool{}
+	for _, span := range recorder.Ended() {
+		for _, link := range span.Links() {
+			linked[link.SpanContext.SpanID()] = true
+		}
+		if span.SpanContext().SpanID() == callSpan.SpanID() {
+			assert.Len(t, span.Links(), len(callers))
+		}
+	}
+	assert.True(t, linked[callSpan.SpanID()], "the callers link the call")
+	for id, caller := range callers {
+		assert.True(t, linked[caller.SpanID()], "the call links %s", id)
+	}
+}
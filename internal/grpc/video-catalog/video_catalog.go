@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/interceptor"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 	videocatalogpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/video_catalog"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
@@ -34,9 +36,8 @@ func (v *VideoCatalogClient) FindAll(ctx context.Context, in *videocatalogpb.Fin
 	ctx, cancel := context.WithTimeout(ctx, v.config.Timeout)
 	defer cancel()
 
-	response, err := v.client.FindAll(ctx, in)
+	response, err := v.client.FindAll(ctx, in, interceptor.Hedgeable())
 	if err != nil {
-		logger.Error("gRPC videoCatalogClient.FindAll failed %v", err)
 		return nil, err
 	}
 
@@ -47,9 +48,8 @@ func (v *VideoCatalogClient) FindById(ctx context.Context, in *videocatalogpb.Fi
 	ctx, cancel := context.WithTimeout(ctx, v.config.Timeout)
 	defer cancel()
 
-	response, err := v.client.FindById(ctx, in)
+	response, err := v.client.FindById(ctx, in, interceptor.Hedgeable())
 	if err != nil {
-		logger.Error("gRPC videoCatalogClient.FindById failed %v", err)
 		return nil, err
 	}
 
@@ -62,12 +62,14 @@ func (v *VideoCatalogClient) Health(ctx context.Context) error {
 
 	res, err := v.health.Check(ctx, &healthpb.HealthCheckRequest{})
 	if err != nil {
-		logger.Error("Video Catalog gRPC health check failed! %v", err)
 		return err
 	}
 
 	if res.Status == healthpb.HealthCheckResponse_NOT_SERVING {
-		logger.Error("Video Catalog gRPC health check failed")
+		logger.Ctx(ctx).Error().
+			Str("service", constant.ServiceVideoCatalog).
+			Str("status", res.Status.String()).
+			Msg("gRPC health check failed")
 		return errors.New("video catalog grpc health check failed")
 	}
 
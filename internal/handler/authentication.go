@@ -22,8 +22,8 @@ func NewAuthHandler(c authrpc.AuthenticationService) *AuthenticationHandler {
 func (a *AuthenticationHandler) Register(c *gin.Context) {
 	var in types.RegisterInput
 	if err := c.ShouldBindJSON(&in); err != nil {
-		res := helper.PrepareResponseFromValidationError(err, &types.RegisterValidationError{})
-		c.JSON(http.StatusBadRequest, res)
+		res := helper.PrepareResponseFromValidationError(c.Request.Context(), err, &in, &types.RegisterValidationError{})
+		helper.RespondWithError(c, http.StatusBadRequest, res)
 		return
 	}
 
@@ -36,7 +36,7 @@ func (a *AuthenticationHandler) Register(c *gin.Context) {
 	res, err := a.authClient.Register(c.Request.Context(), req)
 	if err != nil {
 		status, res := helper.PrepareResponseFromGRPCError(err, &types.RegisterValidationError{})
-		c.JSON(status, res)
+		helper.RespondWithError(c, status, res)
 		return
 	}
 
@@ -46,8 +46,8 @@ func (a *AuthenticationHandler) Register(c *gin.Context) {
 func (a *AuthenticationHandler) Login(c *gin.Context) {
 	var in types.LoginInput
 	if err := c.ShouldBindJSON(&in); err != nil {
-		res := helper.PrepareResponseFromValidationError(err, &types.LoginValidationError{})
-		c.JSON(http.StatusBadRequest, res)
+		res := helper.PrepareResponseFromValidationError(c.Request.Context(), err, &in, &types.LoginValidationError{})
+		helper.RespondWithError(c, http.StatusBadRequest, res)
 		return
 	}
 
@@ -59,7 +59,7 @@ func (a *AuthenticationHandler) Login(c *gin.Context) {
 	res, err := a.authClient.Login(c.Request.Context(), req)
 	if err != nil {
 		status, res := helper.PrepareResponseFromGRPCError(err, &types.LoginValidationError{})
-		c.JSON(status, res)
+		helper.RespondWithError(c, status, res)
 		return
 	}
 
@@ -72,7 +72,7 @@ func (a *AuthenticationHandler) Profile(c *gin.Context) {
 	res := helper.PrepareResponse(constant.MessageOK, gin.H{
 		constant.AuthUser: user,
 	})
-	c.JSON(http.StatusOK, res)
+	helper.Respond(c, http.StatusOK, res)
 }
 
 func (a *AuthenticationHandler) Logout(c *gin.Context) {
@@ -82,7 +82,7 @@ func (a *AuthenticationHandler) Logout(c *gin.Context) {
 	res, err := a.authClient.Logout(c.Request.Context(), &authpb.LogoutRequest{}, h.Token)
 	if err != nil {
 		status, res := helper.PrepareResponseFromGRPCError(err, &types.LogoutValidationError{})
-		c.JSON(status, res)
+		helper.RespondWithError(c, status, res)
 		return
 	}
 
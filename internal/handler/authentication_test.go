@@ -59,13 +59,13 @@ func TestAuthenticationHandler_Register(t *testing.T) {
 			body: types.RegisterInput{
 				Name:     dummyUser.Name,
 				Email:    dummyUser.Email,
-				Password: "secret",
+				Password: "Secret123",
 			},
 			mockSetup: func(m *MockAuthenticationServiceClient) {
 				m.On("Register", mock.Anything, &authpb.RegisterRequest{
 					Name:     dummyUser.Name,
 					Email:    dummyUser.Email,
-					Password: "secret",
+					Password: "Secret123",
 				}).Return(&authpb.RegisterResponse{
 					Message: constant.MessageOK,
 					Data: &authpb.RegisterResponseData{
@@ -107,7 +107,7 @@ func TestAuthenticationHandler_Register(t *testing.T) {
 			body: types.RegisterInput{
 				Name:     dummyUser.Name,
 				Email:    dummyUser.Email,
-				Password: "secret",
+				Password: "Secret123",
 			},
 			mockSetup: func(m *MockAuthenticationServiceClient) {
 				m.On("Register", mock.Anything, mock.Anything).
@@ -116,6 +116,7 @@ func TestAuthenticationHandler_Register(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 		},
@@ -137,6 +138,28 @@ func TestAuthenticationHandler_Register(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "weak password and short name",
+			body: types.RegisterInput{Name: " n ", Email: dummyUser.Email, Password: "secret"},
+			mockSetup: func(m *MockAuthenticationServiceClient) {
+				// no call expected
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON: gin.H{
+				"message": constant.MessageBadRequest,
+				"data": gin.H{
+					"errors": gin.H{
+						"name":  []string{"name must be between 2 and 100 characters"},
+						"email": []string{},
+						"password": []string{
+							"password must be between 8 and 72 characters",
+							"password must contain an uppercase letter",
+							"password must contain a number",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -241,6 +264,7 @@ func TestAuthenticationHandler_Login(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 		},
@@ -322,6 +346,7 @@ func TestAuthenticationHandler_Profile(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 			mockVerifyFunc: mockVerifyTokenError,
@@ -332,6 +357,7 @@ func TestAuthenticationHandler_Profile(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 			expectedJSON: gin.H{
 				"message": constant.MessageUnauthorized,
+				"code":    "UNAUTHENTICATED",
 				"data":    gin.H{},
 			},
 			mockVerifyFunc: mockVerifyTokenSuccess,
@@ -414,6 +440,7 @@ func TestAuthenticationHandler_Logout(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 			mockSetup: func(m *MockAuthenticationServiceClient) {
@@ -428,6 +455,7 @@ func TestAuthenticationHandler_Logout(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 			mockSetup: func(m *MockAuthenticationServiceClient) {
@@ -441,6 +469,7 @@ func TestAuthenticationHandler_Logout(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 			expectedJSON: gin.H{
 				"message": constant.MessageUnauthorized,
+				"code":    "UNAUTHENTICATED",
 				"data":    gin.H{},
 			},
 			mockSetup: func(m *MockAuthenticationServiceClient) {
@@ -1,63 +1,85 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
-	authrpc "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/authentication"
-	uploadrpc "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/upload"
-	videocatalogrpc "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/video-catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/health"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
-	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
-	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
 )
 
+type HealthChecker interface {
+	CheckAll(ctx context.Context) health.Report
+}
+
 type HealthHandler struct {
-	authClient         authrpc.AuthenticationService
-	uploadClient       uploadrpc.UploadService
-	videoCatalogClient videocatalogrpc.VideoCatalogService
+	checker HealthChecker
 }
 
-func NewHealthHandler(clients types.GRPCClients) *HealthHandler {
+func NewHealthHandler(checker HealthChecker) *HealthHandler {
 	return &HealthHandler{
-		authClient:         clients.AuthClient,
-		uploadClient:       clients.UploadClient,
-		videoCatalogClient: clients.VideoCatalogClient,
+		checker: checker,
 	}
 }
 
-func (h *HealthHandler) CheckAll(c *gin.Context) {
-	if !servicesHealthStatus(c, h) {
-		prometheus.ServiceHealth.Set(0)
-		response := helper.PrepareResponse(constant.MessageServicesUnhealthy, gin.H{
-			"status": constant.HealthStatusDegraded,
-		})
-		c.JSON(http.StatusServiceUnavailable, response)
+// Live only reports that the process is serving requests, it never checks
+// dependencies so a backend outage doesn't get the gateway restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	helper.Respond(c, http.StatusOK, helper.PrepareResponse(constant.MessageAlive, gin.H{
+		"status": constant.HealthStatusHealthy,
+	}))
+}
+
+// Ready fails while a critical dependency is unhealthy.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.checker.CheckAll(c.Request.Context())
+
+	if !report.Ready() {
+		helper.Respond(c, http.StatusServiceUnavailable, helper.PrepareResponse(constant.MessageNotReady, gin.H{
+			"status": report.Status(),
+		}))
 		return
 	}
 
-	prometheus.ServiceHealth.Set(1)
-	response := helper.PrepareResponse(constant.MessageServicesHealthy, gin.H{
-		"status": constant.HealthStatusHealthy,
-	})
-	c.JSON(http.StatusOK, response)
+	helper.Respond(c, http.StatusOK, helper.PrepareResponse(constant.MessageReady, gin.H{
+		"status": report.Status(),
+	}))
 }
 
-func servicesHealthStatus(c *gin.Context, h *HealthHandler) bool {
-	ctx := c.Request.Context()
+// CheckAll reports the status, latency and last error of every dependency.
+func (h *HealthHandler) CheckAll(c *gin.Context) {
+	report := h.checker.CheckAll(c.Request.Context())
 
-	if err := h.authClient.Health(ctx); err != nil {
-		return false
-	}
+	services := gin.H{}
+	for name, res := range report.Services {
+		status := constant.HealthStatusHealthy
+		if !res.Healthy {
+			status = constant.HealthStatusUnhealthy
+		}
 
-	if err := h.videoCatalogClient.Health(ctx); err != nil {
-		return false
+		services[name] = gin.H{
+			"status":     status,
+			"critical":   res.Critical,
+			"latency_ms": res.Latency.Milliseconds(),
+			"error":      res.Error,
+			"checked_at": res.CheckedAt,
+			"since":      res.Since,
+		}
 	}
 
-	if err := h.uploadClient.Health(ctx); err != nil {
-		return false
+	code := http.StatusOK
+	message := constant.MessageServicesHealthy
+	if report.Status() != constant.HealthStatusHealthy {
+		message = constant.MessageServicesUnhealthy
+	}
+	if !report.Ready() {
+		code = http.StatusServiceUnavailable
 	}
 
-	return true
+	helper.Respond(c, code, helper.PrepareResponse(message, gin.H{
+		"status":   report.Status(),
+		"services": services,
+	}))
 }
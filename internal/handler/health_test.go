@@ -1,6 +1,7 @@
 package handler_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,95 +11,185 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/handler"
-	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/health"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
-func TestHealthHandler_CheckAll(t *testing.T) {
+type healthMocks struct {
+	auth   *MockAuthenticationServiceClient
+	upload *MockUploadServiceClient
+	video  *MockVideoCatalogServiceClient
+}
+
+// newHealthChecker makes authentication and video catalog critical and
+// upload optional.
+func newHealthChecker(m healthMocks) *health.Checker {
+	return health.NewChecker(
+		health.Dependency{Name: constant.ServiceAuthentication, Critical: true, Check: m.auth.Health},
+		health.Dependency{Name: constant.ServiceUpload, Critical: false, Check: m.upload.Health},
+		health.Dependency{Name: constant.ServiceVideoCatalog, Critical: true, Check: m.video.Health},
+	)
+}
+
+func TestHealthHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
+	healthy := func(m healthMocks) {
+		m.auth.On("Health", mock.Anything).Return(nil).Once()
+		m.upload.On("Health", mock.Anything).Return(nil).Once()
+		m.video.On("Health", mock.Anything).Return(nil).Once()
+	}
+	optionalDown := func(m healthMocks) {
+		m.auth.On("Health", mock.Anything).Return(nil).Once()
+		m.upload.On("Health", mock.Anything).Return(errors.New("upload down")).Once()
+		m.video.On("Health", mock.Anything).Return(nil).Once()
+	}
+	criticalDown := func(m healthMocks) {
+		m.auth.On("Health", mock.Anything).Return(errors.New("grpc failed")).Once()
+		m.upload.On("Health", mock.Anything).Return(nil).Once()
+		m.video.On("Health", mock.Anything).Return(nil).Once()
+	}
+
 	tests := []struct {
-		name           string
-		mockSetup      func(auth *MockAuthenticationServiceClient, upload *MockUploadServiceClient, video *MockVideoCatalogServiceClient)
-		expectedStatus int
-		expectedJSON   gin.H
+		name            string
+		handle          func(h *handler.HealthHandler, c *gin.Context)
+		mockSetup       func(m healthMocks)
+		expectedStatus  int
+		expectedMessage string
+		expectedHealth  string
 	}{
 		{
-			name: "success",
-			mockSetup: func(auth *MockAuthenticationServiceClient, upload *MockUploadServiceClient, video *MockVideoCatalogServiceClient) {
-				auth.On("Health", mock.Anything).
-					Return(nil).
-					Once()
-
-				upload.On("Health", mock.Anything).
-					Return(nil).
-					Once()
-
-				video.On("Health", mock.Anything).
-					Return(nil).
-					Once()
-			},
-			expectedStatus: http.StatusOK,
-			expectedJSON: gin.H{
-				"message": constant.MessageServicesHealthy,
-				"data": gin.H{
-					"status": constant.HealthStatusHealthy,
-				},
-			},
+			name:            "live does not check dependencies",
+			handle:          (*handler.HealthHandler).Live,
+			mockSetup:       func(m healthMocks) {},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: constant.MessageAlive,
+			expectedHealth:  constant.HealthStatusHealthy,
+		},
+		{
+			name:            "ready",
+			handle:          (*handler.HealthHandler).Ready,
+			mockSetup:       healthy,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: constant.MessageReady,
+			expectedHealth:  constant.HealthStatusHealthy,
+		},
+		{
+			name:            "ready with an optional service down",
+			handle:          (*handler.HealthHandler).Ready,
+			mockSetup:       optionalDown,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: constant.MessageReady,
+			expectedHealth:  constant.HealthStatusDegraded,
+		},
+		{
+			name:            "not ready with a critical service down",
+			handle:          (*handler.HealthHandler).Ready,
+			mockSetup:       criticalDown,
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedMessage: constant.MessageNotReady,
+			expectedHealth:  constant.HealthStatusUnhealthy,
+		},
+		{
+			name:            "check all healthy",
+			handle:          (*handler.HealthHandler).CheckAll,
+			mockSetup:       healthy,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: constant.MessageServicesHealthy,
+			expectedHealth:  constant.HealthStatusHealthy,
 		},
 		{
-			name: "unhealthy/grpc error",
-			mockSetup: func(auth *MockAuthenticationServiceClient, upload *MockUploadServiceClient, video *MockVideoCatalogServiceClient) {
-				// Only mock the first one the handler will check.
-				auth.On("Health", mock.Anything).
-					Return(errors.New("grpc failed")).
-					Once()
-				// No mocks for upload/video because they won’t be called.
-			},
-			expectedStatus: http.StatusServiceUnavailable,
-			expectedJSON: gin.H{
-				"message": constant.MessageServicesUnhealthy,
-				"data": gin.H{
-					"status": constant.HealthStatusDegraded,
-				},
-			},
+			name:            "check all degraded",
+			handle:          (*handler.HealthHandler).CheckAll,
+			mockSetup:       optionalDown,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: constant.MessageServicesUnhealthy,
+			expectedHealth:  constant.HealthStatusDegraded,
+		},
+		{
+			name:            "check all unhealthy checks every service",
+			handle:          (*handler.HealthHandler).CheckAll,
+			mockSetup:       criticalDown,
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedMessage: constant.MessageServicesUnhealthy,
+			expectedHealth:  constant.HealthStatusUnhealthy,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockAuthenticationClient := new(MockAuthenticationServiceClient)
-			mockUploadClient := new(MockUploadServiceClient)
-			mockVideoCatalogSvc := new(MockVideoCatalogServiceClient)
-
-			tt.mockSetup(mockAuthenticationClient, mockUploadClient, mockVideoCatalogSvc)
+			m := healthMocks{
+				auth:   new(MockAuthenticationServiceClient),
+				upload: new(MockUploadServiceClient),
+				video:  new(MockVideoCatalogServiceClient),
+			}
+			tt.mockSetup(m)
 
-			h := handler.NewHealthHandler(types.GRPCClients{
-				AuthClient:         mockAuthenticationClient,
-				UploadClient:       mockUploadClient,
-				VideoCatalogClient: mockVideoCatalogSvc,
-			})
+			h := handler.NewHealthHandler(newHealthChecker(m))
 
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
-
 			c.Request = httptest.NewRequest(http.MethodGet, "/health", nil)
-			c.Request.Header.Set("Content-Type", "application/json")
 
-			h.CheckAll(c)
+			tt.handle(h, c)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 
-			expectedBody, err := json.Marshal(tt.expectedJSON)
-			require.NoError(t, err)
+			var body struct {
+				Message string `json:"message"`
+				Data    struct {
+					Status string `json:"status"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedMessage, body.Message)
+			assert.Equal(t, tt.expectedHealth, body.Data.Status)
+
+			m.auth.AssertExpectations(t)
+			m.upload.AssertExpectations(t)
+			m.video.AssertExpectations(t)
+		})
+	}
+}
 
-			assert.JSONEq(t, string(expectedBody), w.Body.String())
+func TestHealthHandler_CheckAll_ReportsEveryService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
-			mockAuthenticationClient.AssertExpectations(t)
-			mockUploadClient.AssertExpectations(t)
-			mockVideoCatalogSvc.AssertExpectations(t)
-		})
+	m := healthMocks{
+		auth:   new(MockAuthenticationServiceClient),
+		upload: new(MockUploadServiceClient),
+		video:  new(MockVideoCatalogServiceClient),
+	}
+	m.auth.On("Health", mock.Anything).Return(nil)
+	m.upload.On("Health", mock.Anything).Return(errors.New("upload down"))
+	m.video.On("Health", mock.Anything).Return(nil)
+
+	h := handler.NewHealthHandler(newHealthChecker(m))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/health", nil).WithContext(context.Background())
+
+	h.CheckAll(c)
+
+	var body struct {
+		Data struct {
+			Services map[string]struct {
+				Status    string `json:"status"`
+				Critical  bool   `json:"critical"`
+				LatencyMs int64  `json:"latency_ms"`
+				Error     string `json:"error"`
+			} `json:"services"`
+		} `json:"data"`
 	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	require.Len(t, body.Data.Services, 3)
+	assert.Equal(t, constant.HealthStatusHealthy, body.Data.Services[constant.ServiceAuthentication].Status)
+	assert.True(t, body.Data.Services[constant.ServiceAuthentication].Critical)
+	assert.Equal(t, constant.HealthStatusUnhealthy, body.Data.Services[constant.ServiceUpload].Status)
+	assert.False(t, body.Data.Services[constant.ServiceUpload].Critical)
+	assert.Equal(t, "upload down", body.Data.Services[constant.ServiceUpload].Error)
 }
@@ -26,7 +26,7 @@ func (u *UploadHandler) CreatePresignedUrl(c *gin.Context) {
 	res, err := u.uploadClient.CreatePresignedUrl(c.Request.Context(), &uploadpb.CreatePresignedUrlRequest{})
 	if err != nil {
 		status, res := helper.PrepareResponseFromGRPCError(err, gin.H{})
-		c.JSON(status, res)
+		helper.RespondWithError(c, status, res)
 		return
 	}
 
@@ -36,16 +36,19 @@ func (u *UploadHandler) CreatePresignedUrl(c *gin.Context) {
 func (u *UploadHandler) UploadedWebhook(c *gin.Context) {
 	user, exists := c.Get(constant.AuthUser)
 	if !exists {
-		logger.Error("Authenticated user does not exists in context!")
-		c.JSON(http.StatusInternalServerError, helper.PrepareResponse(constant.MessageInternalServerError, gin.H{}))
+		logger.Ctx(c.Request.Context()).Error().
+			Str("service", constant.ServiceUpload).
+			Str("method", "UploadedWebhook").
+			Msg("Authenticated user does not exist in context")
+		helper.RespondWithError(c, http.StatusInternalServerError, helper.PrepareResponse(constant.MessageInternalServerError, gin.H{}))
 		return
 	}
 	authUser := user.(*authpb.User)
 
 	var in types.UploadedWebhookInput
 	if err := c.ShouldBind(&in); err != nil {
-		res := helper.PrepareResponseFromValidationError(err, &types.UploadedWebhookValidationError{})
-		c.JSON(http.StatusBadRequest, res)
+		res := helper.PrepareResponseFromValidationError(c.Request.Context(), err, &in, &types.UploadedWebhookValidationError{})
+		helper.RespondWithError(c, http.StatusBadRequest, res)
 		return
 	}
 
@@ -59,7 +62,7 @@ func (u *UploadHandler) UploadedWebhook(c *gin.Context) {
 	res, err := u.uploadClient.UploadedWebhook(c.Request.Context(), req, strconv.Itoa(int(authUser.Id)))
 	if err != nil {
 		status, res := helper.PrepareResponseFromGRPCError(err, &types.UploadedWebhookValidationError{})
-		c.JSON(status, res)
+		helper.RespondWithError(c, status, res)
 		return
 	}
 
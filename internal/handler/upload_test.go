@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -65,6 +66,7 @@ func TestUploadHandler_CreatePresignedUrl(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 			mockVerifyFunc: mockVerifyTokenSuccess,
@@ -75,6 +77,7 @@ func TestUploadHandler_CreatePresignedUrl(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 			mockSetup: func(m *MockUploadServiceClient) {
@@ -88,6 +91,7 @@ func TestUploadHandler_CreatePresignedUrl(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 			expectedJSON: gin.H{
 				"message": constant.MessageUnauthorized,
+				"code":    "UNAUTHENTICATED",
 				"data":    gin.H{},
 			},
 			mockSetup: func(m *MockUploadServiceClient) {
@@ -161,8 +165,8 @@ func TestUploadHandler_UploadedWebhook(t *testing.T) {
 	}
 
 	reqBody := types.UploadedWebhookInput{
-		VideoId:     "1",
-		ThumbnailId: "1",
+		VideoId:     "0f3c7a56-1d2e-4b8a-9c4f-5e6d7a8b9c0d",
+		ThumbnailId: "7b1e2c3d-4f5a-4b6c-8d7e-9f0a1b2c3d4e",
 		Title:       "title",
 		Description: "description",
 	}
@@ -208,6 +212,7 @@ func TestUploadHandler_UploadedWebhook(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 			mockVerifyFunc: mockVerifyTokenSuccess,
@@ -218,6 +223,7 @@ func TestUploadHandler_UploadedWebhook(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 			mockSetup: func(m *MockUploadServiceClient) {
@@ -231,6 +237,7 @@ func TestUploadHandler_UploadedWebhook(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 			expectedJSON: gin.H{
 				"message": constant.MessageUnauthorized,
+				"code":    "UNAUTHENTICATED",
 				"data":    gin.H{},
 			},
 			mockSetup: func(m *MockUploadServiceClient) {
@@ -289,6 +296,32 @@ func TestUploadHandler_UploadedWebhook(t *testing.T) {
 			mockVerifyFunc: mockVerifyTokenSuccess,
 			authToken:      "token",
 		},
+		{
+			name: "invalid ids and too long fields",
+			body: types.UploadedWebhookInput{
+				VideoId:     "1",
+				ThumbnailId: "not-a-uuid",
+				Title:       strings.Repeat("t", 256),
+				Description: strings.Repeat("d", 5001),
+			},
+			mockSetup: func(m *MockUploadServiceClient) {
+				// no call expected
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON: gin.H{
+				"message": constant.MessageBadRequest,
+				"data": gin.H{
+					"errors": gin.H{
+						"video_id":     []string{"video_id must be a valid UUID"},
+						"thumbnail_id": []string{"thumbnail_id must be a valid UUID"},
+						"title":        []string{"title must be at most 255 characters"},
+						"description":  []string{"description must be at most 5000 characters"},
+					},
+				},
+			},
+			mockVerifyFunc: mockVerifyTokenSuccess,
+			authToken:      "token",
+		},
 	}
 
 	for _, tt := range tests {
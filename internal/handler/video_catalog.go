@@ -10,6 +10,7 @@ import (
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 	videocatalogpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/video_catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
 )
 
 type VideoCatalogHandler struct {
@@ -21,10 +22,29 @@ func NewVideoCatalogHandler(c videocatalogrpc.VideoCatalogService) *VideoCatalog
 }
 
 func (v *VideoCatalogHandler) FindAll(c *gin.Context) {
-	res, err := v.videoCatalogClient.FindAll(c.Request.Context(), &videocatalogpb.FindAllRequest{})
+	var in types.FindAllVideosInput
+	if err := c.ShouldBindQuery(&in); err != nil {
+		res := helper.PrepareResponseFromQueryBindError(c.Request.Context(), err, c.Request.URL.Query(), &in, &types.FindAllVideosValidationError{})
+		helper.RespondWithError(c, http.StatusBadRequest, res)
+		return
+	}
+
+	req := &videocatalogpb.FindAllRequest{
+		Page:       in.Page,
+		Limit:      in.Limit,
+		Cursor:     in.Cursor,
+		SortBy:     in.SortBy,
+		SortOrder:  in.SortOrder,
+		Resolution: in.Resolution,
+	}
+	if in.UserId != 0 {
+		req.UserId = &in.UserId
+	}
+
+	res, err := v.videoCatalogClient.FindAll(c.Request.Context(), req)
 	if err != nil {
 		status, res := helper.PrepareResponseFromGRPCError(err, gin.H{})
-		c.JSON(status, res)
+		helper.RespondWithError(c, status, res)
 		return
 	}
 
@@ -35,8 +55,13 @@ func (v *VideoCatalogHandler) FindById(c *gin.Context) {
 	videoId := c.Param("id")
 	id, err := strconv.Atoi(videoId)
 	if err != nil {
-		logger.Error("Unable to parse video id %v, %v", err, videoId)
-		c.JSON(http.StatusBadRequest, helper.PrepareResponse(constant.MessageBadRequest, gin.H{}))
+		logger.Ctx(c.Request.Context()).Warn().
+			Str("service", constant.ServiceVideoCatalog).
+			Str("method", "FindById").
+			Str("id", videoId).
+			Err(err).
+			Msg("Unable to parse video id")
+		helper.RespondWithError(c, http.StatusBadRequest, helper.PrepareResponse(constant.MessageBadRequest, gin.H{}))
 		return
 	}
 
@@ -47,7 +72,7 @@ func (v *VideoCatalogHandler) FindById(c *gin.Context) {
 	res, err := v.videoCatalogClient.FindById(c.Request.Context(), req)
 	if err != nil {
 		status, res := helper.PrepareResponseFromGRPCError(err, gin.H{})
-		c.JSON(status, res)
+		helper.RespondWithError(c, status, res)
 		return
 	}
 
@@ -34,8 +34,27 @@ var dummyVideo = &videocatalogpb.Video{
 func TestVideoCatalogHandler_FindAll(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
+	userId := int32(3)
+
+	emptyErrors := func(overrides gin.H) gin.H {
+		errs := gin.H{
+			"page":       []string{},
+			"limit":      []string{},
+			"cursor":     []string{},
+			"sort_by":    []string{},
+			"sort_order": []string{},
+			"user_id":    []string{},
+			"resolution": []string{},
+		}
+		for k, v := range overrides {
+			errs[k] = v
+		}
+		return gin.H{"message": constant.MessageBadRequest, "data": gin.H{"errors": errs}}
+	}
+
 	tests := []struct {
 		name           string
+		query          string
 		mockSetup      func(m *MockVideoCatalogServiceClient)
 		expectedStatus int
 		expectedJSON   gin.H
@@ -74,6 +93,68 @@ func TestVideoCatalogHandler_FindAll(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "pagination, sorting and filtering",
+			query: "?limit=10&cursor=abc&sort_by=duration&sort_order=desc&user_id=3&resolution=1080p",
+			mockSetup: func(m *MockVideoCatalogServiceClient) {
+				m.On("FindAll", mock.Anything, &videocatalogpb.FindAllRequest{
+					Limit:      10,
+					Cursor:     "abc",
+					SortBy:     "duration",
+					SortOrder:  "desc",
+					UserId:     &userId,
+					Resolution: "1080p",
+				}).
+					Return(&videocatalogpb.FindAllResponse{
+						Message: constant.MessageOK,
+						Data: &videocatalogpb.FindAllResponseData{
+							NextCursor: "def",
+							Total:      25,
+							Limit:      10,
+							TotalPages: 3,
+						},
+					}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedJSON: gin.H{
+				"message": constant.MessageOK,
+				"data": gin.H{
+					"next_cursor": "def",
+					"total":       25,
+					"limit":       10,
+					"total_pages": 3,
+				},
+			},
+		},
+		{
+			name:           "invalid values",
+			query:          "?limit=500&sort_by=title&sort_order=up&resolution=1080-p",
+			mockSetup:      func(m *MockVideoCatalogServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON: emptyErrors(gin.H{
+				"limit":      []string{"limit is too large"},
+				"sort_by":    []string{"sort_by is not a supported value"},
+				"sort_order": []string{"sort_order is not a supported value"},
+				"resolution": []string{"resolution must only contain letters and numbers"},
+			}),
+		},
+		{
+			name:           "cursor with page",
+			query:          "?page=2&cursor=abc",
+			mockSetup:      func(m *MockVideoCatalogServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON: emptyErrors(gin.H{
+				"cursor": []string{"cursor can not be combined with the other given fields"},
+			}),
+		},
+		{
+			name:           "non numeric value",
+			query:          "?page=two",
+			mockSetup:      func(m *MockVideoCatalogServiceClient) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   emptyErrors(gin.H{"page": []string{"page is invalid"}}),
+		},
 		{
 			name: "grpc error",
 			mockSetup: func(m *MockVideoCatalogServiceClient) {
@@ -84,6 +165,7 @@ func TestVideoCatalogHandler_FindAll(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 		},
@@ -99,7 +181,7 @@ func TestVideoCatalogHandler_FindAll(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
 
-			c.Request = httptest.NewRequest(http.MethodGet, "/videos", nil)
+			c.Request = httptest.NewRequest(http.MethodGet, "/videos"+tt.query, nil)
 			c.Request.Header.Set("Content-Type", "application/json")
 
 			h.FindAll(c)
@@ -172,6 +254,7 @@ func TestVideoCatalogHandler_FindById(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedJSON: gin.H{
 				"message": constant.MessageInternalServerError,
+				"code":    "UNKNOWN",
 				"data":    gin.H{},
 			},
 		},
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+// Dependency is a downstream service the gateway needs. The gateway is only
+// ready while every critical dependency is healthy, optional ones just
+// degrade it.
+type Dependency struct {
+	Name     string
+	Critical bool
+	Check    CheckFunc
+}
+
+type Result struct {
+	Healthy   bool
+	Critical  bool
+	Latency   time.Duration
+	Error     string
+	CheckedAt time.Time
+	// Since is when the service went up or down, the Prober keeps it across
+	// checks.
+	Since time.Time
+}
+
+type Report struct {
+	Services map[string]Result
+	// ShuttingDown makes the gateway unready regardless of its dependencies.
+	ShuttingDown bool
+}
+
+// Status is healthy when every dependency is, degraded when only optional
+// dependencies fail and unhealthy when a critical one does.
+func (r Report) Status() string {
+	if r.ShuttingDown {
+		return constant.HealthStatusShuttingDown
+	}
+
+	status := constant.HealthStatusHealthy
+	for _, res := range r.Services {
+		if res.Healthy {
+			continue
+		}
+		if res.Critical {
+			return constant.HealthStatusUnhealthy
+		}
+		status = constant.HealthStatusDegraded
+	}
+
+	return status
+}
+
+func (r Report) Ready() bool {
+	status := r.Status()
+	return status != constant.HealthStatusUnhealthy && status != constant.HealthStatusShuttingDown
+}
+
+type Checker struct {
+	deps []Dependency
+	now  func() time.Time
+}
+
+func NewChecker(deps ...Dependency) *Checker {
+	return &Checker{deps: deps, now: time.Now}
+}
+
+func (c *Checker) Dependencies() []Dependency {
+	return c.deps
+}
+
+// CheckAll checks every dependency concurrently and updates their health
+// gauge.
+func (c *Checker) CheckAll(ctx context.Context) Report {
+	report := Report{Services: make(map[string]Result, len(c.deps))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, dep := range c.deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res := c.check(ctx, dep)
+
+			mu.Lock()
+			report.Services[dep.Name] = res
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (c *Checker) check(ctx context.Context, dep Dependency) Result {
+	start := c.now()
+	err := dep.Check(ctx)
+
+	res := Result{
+		Healthy:   err == nil,
+		Critical:  dep.Critical,
+		Latency:   c.now().Sub(start),
+		CheckedAt: start,
+		Since:     start,
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	gauge := 0.0
+	if res.Healthy {
+		gauge = 1
+	}
+	prometheus.ServiceHealth.WithLabelValues(dep.Name).Set(gauge)
+
+	return res
+}
@@ -0,0 +1,102 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/health"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_Status(t *testing.T) {
+	tests := []struct {
+		name          string
+		services      map[string]health.Result
+		expected      string
+		expectedReady bool
+	}{
+		{
+			name: "all healthy",
+			services: map[string]health.Result{
+				"a": {Healthy: true, Critical: true},
+				"b": {Healthy: true},
+			},
+			expected:      constant.HealthStatusHealthy,
+			expectedReady: true,
+		},
+		{
+			name: "optional service down",
+			services: map[string]health.Result{
+				"a": {Healthy: true, Critical: true},
+				"b": {Healthy: false},
+			},
+			expected:      constant.HealthStatusDegraded,
+			expectedReady: true,
+		},
+		{
+			name: "critical service down",
+			services: map[string]health.Result{
+				"a": {Healthy: false, Critical: true},
+				"b": {Healthy: false},
+			},
+			expected:      constant.HealthStatusUnhealthy,
+			expectedReady: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := health.Report{Services: tt.services}
+
+			assert.Equal(t, tt.expected, report.Status())
+			assert.Equal(t, tt.expectedReady, report.Ready())
+		})
+	}
+}
+
+func TestChecker_CheckAll(t *testing.T) {
+	slow := func(err error) health.CheckFunc {
+		return func(ctx context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return err
+		}
+	}
+
+	checker := health.NewChecker(
+		health.Dependency{Name: "checker_a", Critical: true, Check: slow(nil)},
+		health.Dependency{Name: "checker_b", Check: slow(errors.New("connection refused"))},
+		health.Dependency{Name: "checker_c", Check: slow(nil)},
+	)
+
+	start := time.Now()
+	report := checker.CheckAll(context.Background())
+
+	assert.Less(t, time.Since(start), 250*time.Millisecond, "dependencies should be checked concurrently")
+	require.Len(t, report.Services, 3)
+
+	a := report.Services["checker_a"]
+	assert.True(t, a.Healthy)
+	assert.True(t, a.Critical)
+	assert.GreaterOrEqual(t, a.Latency, 100*time.Millisecond)
+	assert.Empty(t, a.Error)
+
+	b := report.Services["checker_b"]
+	assert.False(t, b.Healthy)
+	assert.Equal(t, "connection refused", b.Error)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.ServiceHealth.WithLabelValues("checker_a")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(prometheus.ServiceHealth.WithLabelValues("checker_b")))
+}
+
+func TestChecker_CheckAll_NoDependencies(t *testing.T) {
+	report := health.NewChecker().CheckAll(context.Background())
+
+	assert.Empty(t, report.Services)
+	assert.Equal(t, constant.HealthStatusHealthy, report.Status())
+}
@@ -0,0 +1,160 @@
+package health
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+)
+
+// Prober checks the dependencies in the background and serves the latest
+// report from memory, so health probes don't call the backends.
+type Prober struct {
+	checker  *Checker
+	interval time.Duration
+	jitter   float64
+
+	mu           sync.RWMutex
+	report       Report
+	probed       bool
+	ready        bool
+	shuttingDown bool
+}
+
+// maxJitter keeps the jittered interval positive.
+const maxJitter = 0.9
+
+// NewProber probes every interval, +/- jitter (a fraction of the interval) so
+// gateway replicas don't probe the backends in lockstep. jitter is clamped to
+// [0, maxJitter].
+func NewProber(checker *Checker, interval time.Duration, jitter float64) *Prober {
+	jitter = min(max(jitter, 0), maxJitter)
+
+	return &Prober{
+		checker:  checker,
+		interval: interval,
+		jitter:   jitter,
+	}
+}
+
+// Run probes right away and then on every interval until ctx is done.
+func (p *Prober) Run(ctx context.Context) {
+	for {
+		p.Probe(ctx)
+
+		timer := time.NewTimer(p.nextInterval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// CheckAll returns the cached report, it only probes when nothing has been
+// probed yet.
+func (p *Prober) CheckAll(ctx context.Context) Report {
+	p.mu.RLock()
+	report, probed := p.report, p.probed
+	report.ShuttingDown = p.shuttingDown
+	p.mu.RUnlock()
+
+	if !probed {
+		return p.Probe(ctx)
+	}
+
+	return report
+}
+
+// Probe checks every dependency and caches the report.
+func (p *Prober) Probe(ctx context.Context) Report {
+	report := p.checker.CheckAll(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	report.ShuttingDown = p.shuttingDown
+
+	for name, res := range report.Services {
+		prev, ok := p.report.Services[name]
+		if ok && prev.Healthy == res.Healthy {
+			res.Since = prev.Since
+			report.Services[name] = res
+			continue
+		}
+
+		if ok {
+			logTransition(ctx, name, res)
+		}
+	}
+
+	ready := report.Ready()
+	if !p.probed || ready != p.ready {
+		logReadiness(ctx, ready, report)
+	}
+
+	p.report = report
+	p.probed = true
+	p.ready = ready
+
+	return report
+}
+
+// MarkShuttingDown makes the gateway unready for good, it is the first step of
+// a graceful shutdown.
+func (p *Prober) MarkShuttingDown(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.shuttingDown = true
+	p.report.ShuttingDown = true
+	if p.ready {
+		logReadiness(ctx, false, p.report)
+	}
+	p.ready = false
+
+	return nil
+}
+
+func (p *Prober) nextInterval() time.Duration {
+	interval := float64(p.interval)
+	if p.jitter > 0 {
+		interval *= 1 + p.jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(interval)
+}
+
+func logTransition(ctx context.Context, name string, res Result) {
+	status := "up"
+	event := logger.Ctx(ctx).Info()
+	if !res.Healthy {
+		status = "down"
+		event = logger.Ctx(ctx).Warn().Str("error", res.Error)
+	}
+
+	prometheus.ServiceHealthTransitions.WithLabelValues(name, status).Inc()
+	event.
+		Str("service", name).
+		Bool("critical", res.Critical).
+		Msgf("Service went %s", status)
+}
+
+func logReadiness(ctx context.Context, ready bool, report Report) {
+	gauge := 0.0
+	event := logger.Ctx(ctx).Warn()
+	if ready {
+		gauge = 1
+		event = logger.Ctx(ctx).Info()
+	}
+
+	prometheus.GatewayReady.Set(gauge)
+	event.
+		Bool("ready", ready).
+		Str("status", report.Status()).
+		Msg("Gateway readiness changed")
+}
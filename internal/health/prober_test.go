@@ -0,0 +1,119 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/health"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// switchable is a dependency whose health can be flipped by the test.
+type switchable struct {
+	calls atomic.Int32
+	err   atomic.Pointer[error]
+}
+
+func (s *switchable) Check(ctx context.Context) error {
+	s.calls.Add(1)
+	if err := s.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (s *switchable) fail(err error) {
+	s.err.Store(&err)
+}
+
+func (s *switchable) recover() {
+	s.err.Store(nil)
+}
+
+func TestProber_CheckAll_ServesCachedReport(t *testing.T) {
+	dep := &switchable{}
+	prober := health.NewProber(health.NewChecker(health.Dependency{Name: "prober_cached", Check: dep.Check}), time.Hour, 0)
+
+	for range 3 {
+		report := prober.CheckAll(context.Background())
+		assert.True(t, report.Services["prober_cached"].Healthy)
+	}
+
+	assert.EqualValues(t, 1, dep.calls.Load(), "only the first call should probe")
+}
+
+func TestProber_Probe_TracksTransitions(t *testing.T) {
+	dep := &switchable{}
+	prober := health.NewProber(health.NewChecker(health.Dependency{Name: "prober_flip", Critical: true, Check: dep.Check}), time.Hour, 0)
+
+	first := prober.Probe(context.Background())
+	wentUp := first.Services["prober_flip"].Since
+	assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.GatewayReady))
+
+	time.Sleep(5 * time.Millisecond)
+	second := prober.Probe(context.Background())
+	assert.Equal(t, wentUp, second.Services["prober_flip"].Since, "since should not move while the status is unchanged")
+
+	dep.fail(errors.New("connection refused"))
+	down := prober.Probe(context.Background())
+
+	res := down.Services["prober_flip"]
+	assert.False(t, res.Healthy)
+	assert.Equal(t, "connection refused", res.Error)
+	assert.True(t, res.Since.After(wentUp))
+	assert.False(t, down.Ready())
+	assert.Equal(t, 0.0, testutil.ToFloat64(prometheus.GatewayReady))
+	assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.ServiceHealthTransitions.WithLabelValues("prober_flip", "down")))
+
+	dep.recover()
+	up := prober.Probe(context.Background())
+
+	assert.True(t, up.Ready())
+	assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.GatewayReady))
+	assert.Equal(t, 1.0, testutil.ToFloat64(prometheus.ServiceHealthTransitions.WithLabelValues("prober_flip", "up")))
+	assert.Equal(t, up, prober.CheckAll(context.Background()))
+}
+
+func TestProber_Run(t *testing.T) {
+	dep := &switchable{}
+	prober := health.NewProber(health.NewChecker(health.Dependency{Name: "prober_run", Check: dep.Check}), 10*time.Millisecond, 0.5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		prober.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return dep.calls.Load() >= 3 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+func TestProber_MarkShuttingDown(t *testing.T) {
+	dep := &switchable{}
+	prober := health.NewProber(health.NewChecker(health.Dependency{Name: "prober_shutdown", Critical: true, Check: dep.Check}), time.Hour, 0)
+
+	require.True(t, prober.Probe(context.Background()).Ready())
+
+	require.NoError(t, prober.MarkShuttingDown(context.Background()))
+
+	report := prober.CheckAll(context.Background())
+	assert.False(t, report.Ready())
+	assert.Equal(t, constant.HealthStatusShuttingDown, report.Status())
+	assert.Equal(t, 0.0, testutil.ToFloat64(prometheus.GatewayReady))
+
+	assert.False(t, prober.Probe(context.Background()).Ready(), "a later probe must not make the gateway ready again")
+}
@@ -1,20 +1,26 @@
 package helper
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -33,27 +39,20 @@ func PrepareResponse(message string, data any) gin.H {
 	}
 }
 
-func PrepareResponseFromValidationError(err error, obj any) gin.H {
+// PrepareResponseFromValidationError reports every rule of in a field fails,
+// keyed like obj, in the locale of ctx.
+func PrepareResponseFromValidationError(ctx context.Context, err error, in any, obj any) gin.H {
 	errorsMap := map[string][]string{}
 
-	if ve, ok := err.(validator.ValidationErrors); ok {
-		for _, e := range ve {
-			f, _ := reflect.TypeOf(obj).Elem().FieldByName(e.Field())
-			field, _ := f.Tag.Lookup("json")
-			errorsMap[field] = []string{ValidationErrorByTag(e.Tag(), field)}
-		}
-
-		// Add empty slices for fields without errors to keep structure consistent
-		fields := reflect.VisibleFields(reflect.Indirect(reflect.ValueOf(obj)).Type())
-		for _, field := range fields {
-			t, _ := field.Tag.Lookup("json")
-			if _, ok := errorsMap[t]; !ok {
-				errorsMap[t] = []string{}
-			}
+	if _, ok := err.(validator.ValidationErrors); ok {
+		for _, f := range validation.Failures(err, in) {
+			o, _ := reflect.TypeOf(obj).Elem().FieldByName(f.Field)
+			field, _ := o.Tag.Lookup("json")
+			errorsMap[field] = append(errorsMap[field], f.Messages(i18n.FromContext(ctx), field)...)
 		}
 
 		return PrepareResponse(constant.MessageBadRequest, gin.H{
-			"errors": errorsMap,
+			"errors": withEmptyFields(errorsMap, obj),
 		})
 	}
 
@@ -62,55 +61,174 @@ func PrepareResponseFromValidationError(err error, obj any) gin.H {
 	})
 }
 
-func ValidationErrorByTag(tag string, field string) string {
-	switch tag {
-	case "required":
-		return fmt.Sprintf("%s is required", field)
-	case "invalid":
-		return fmt.Sprintf("%s is invalid", field)
-	case "email":
-		return fmt.Sprintf("%s must be an email", field)
+// PrepareResponseFromQueryBindError is PrepareResponseFromValidationError for
+// ShouldBindQuery. Values that don't parse into a numeric field of in fail
+// binding before validation runs, they are reported as invalid here.
+func PrepareResponseFromQueryBindError(ctx context.Context, err error, query url.Values, in any, obj any) gin.H {
+	if _, ok := err.(validator.ValidationErrors); ok {
+		return PrepareResponseFromValidationError(ctx, err, in, obj)
+	}
+
+	errorsMap := map[string][]string{}
+
+	for _, f := range reflect.VisibleFields(reflect.TypeOf(in).Elem()) {
+		key, ok := f.Tag.Lookup("form")
+		if !ok || query.Get(key) == "" {
+			continue
+		}
+
+		var parseErr error
+		switch f.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			_, parseErr = strconv.ParseInt(query.Get(key), 10, f.Type.Bits())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			_, parseErr = strconv.ParseUint(query.Get(key), 10, f.Type.Bits())
+		case reflect.Float32, reflect.Float64:
+			_, parseErr = strconv.ParseFloat(query.Get(key), f.Type.Bits())
+		case reflect.Bool:
+			_, parseErr = strconv.ParseBool(query.Get(key))
+		}
+
+		if parseErr != nil {
+			o, _ := reflect.TypeOf(obj).Elem().FieldByName(f.Name)
+			field, _ := o.Tag.Lookup("json")
+			errorsMap[field] = []string{ValidationErrorByTag(i18n.FromContext(ctx), "invalid", field)}
+		}
+	}
+
+	return PrepareResponse(constant.MessageBadRequest, gin.H{
+		"errors": withEmptyFields(errorsMap, obj),
+	})
+}
+
+// withEmptyFields adds empty slices for fields without errors to keep the
+// structure consistent.
+func withEmptyFields(errorsMap map[string][]string, obj any) map[string][]string {
+	fields := reflect.VisibleFields(reflect.Indirect(reflect.ValueOf(obj)).Type())
+	for _, field := range fields {
+		t, _ := field.Tag.Lookup("json")
+		if _, ok := errorsMap[t]; !ok {
+			errorsMap[t] = []string{}
+		}
 	}
-	return ""
+
+	return errorsMap
+}
+
+// ValidationErrorByTag renders the catalog message of a validation rule.
+func ValidationErrorByTag(locale string, tag string, field string) string {
+	return validation.Message(locale, tag, field, "")
 }
 
+// PrepareResponseFromGRPCError maps a gRPC error to its HTTP status and
+// response. The response has the upper snake case gRPC code, e.g.
+// "DEADLINE_EXCEEDED", next to the message, and the google.rpc.Status details
+// the backend attached:
+//   - BadRequest field violations as "errors", keyed like obj
+//   - RetryInfo as "retry_after" in seconds
+//   - ErrorInfo as "reason", "domain" and "metadata"
+//   - LocalizedMessage replacing the message
 func PrepareResponseFromGRPCError(err error, obj any) (int, gin.H) {
-	e, _ := status.FromError(err)
+	e, ok := status.FromError(err)
+	if !ok {
+		// e.g. the request context ending before the call was made
+		e = status.FromContextError(err)
+	}
 
 	s := GRPCToHttpCode(e.Code())
+	message := HTTPCodeToMessage(s)
 	data := gin.H{}
 
-	if s == http.StatusBadRequest {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range e.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			violations = append(violations, d.GetFieldViolations()...)
+		case *errdetails.RetryInfo:
+			data["retry_after"] = int(math.Ceil(d.GetRetryDelay().AsDuration().Seconds()))
+		case *errdetails.ErrorInfo:
+			data["reason"] = d.GetReason()
+			data["domain"] = d.GetDomain()
+			if len(d.GetMetadata()) > 0 {
+				data["metadata"] = d.GetMetadata()
+			}
+		case *errdetails.LocalizedMessage:
+			if d.GetMessage() != "" {
+				message = d.GetMessage()
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		data["errors"] = fieldViolationErrors(violations, obj)
+	} else if s == http.StatusBadRequest {
+		// backends without error details send the field errors as JSON in the
+		// status message
 		json.Unmarshal([]byte(e.Message()), &obj)
 		data["errors"] = obj
 	}
 
-	res := PrepareResponse(HTTPCodeToMessage(s), data)
+	res := PrepareResponse(message, data)
+	res["code"] = GRPCCodeName(e.Code())
 
 	return s, res
 }
 
+// fieldViolationErrors groups violations by field, with an empty list for
+// every other field of obj when it is a validation error struct.
+func fieldViolationErrors(violations []*errdetails.BadRequest_FieldViolation, obj any) map[string][]string {
+	errorsMap := map[string][]string{}
+	for _, v := range violations {
+		errorsMap[v.GetField()] = append(errorsMap[v.GetField()], v.GetDescription())
+	}
+
+	if t := reflect.TypeOf(obj); t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		return withEmptyFields(errorsMap, obj)
+	}
+
+	return errorsMap
+}
+
+var grpcCodes = map[codes.Code]struct {
+	name   string
+	status int
+}{
+	codes.OK:                 {"OK", http.StatusOK},
+	codes.Canceled:           {"CANCELLED", constant.StatusClientClosedRequest},
+	codes.Unknown:            {"UNKNOWN", http.StatusInternalServerError},
+	codes.InvalidArgument:    {"INVALID_ARGUMENT", http.StatusBadRequest},
+	codes.DeadlineExceeded:   {"DEADLINE_EXCEEDED", http.StatusGatewayTimeout},
+	codes.NotFound:           {"NOT_FOUND", http.StatusNotFound},
+	codes.AlreadyExists:      {"ALREADY_EXISTS", http.StatusConflict},
+	codes.PermissionDenied:   {"PERMISSION_DENIED", http.StatusForbidden},
+	codes.ResourceExhausted:  {"RESOURCE_EXHAUSTED", http.StatusTooManyRequests},
+	codes.FailedPrecondition: {"FAILED_PRECONDITION", http.StatusBadRequest},
+	codes.Aborted:            {"ABORTED", http.StatusConflict},
+	codes.OutOfRange:         {"OUT_OF_RANGE", http.StatusBadRequest},
+	codes.Unimplemented:      {"UNIMPLEMENTED", http.StatusNotImplemented},
+	codes.Internal:           {"INTERNAL", http.StatusInternalServerError},
+	codes.Unavailable:        {"UNAVAILABLE", http.StatusServiceUnavailable},
+	codes.DataLoss:           {"DATA_LOSS", http.StatusInternalServerError},
+	codes.Unauthenticated:    {"UNAUTHENTICATED", http.StatusUnauthorized},
+}
+
+// GRPCToHttpCode follows the mapping of google.rpc.Code, unknown codes are 500.
 func GRPCToHttpCode(code codes.Code) int {
-	switch code {
-	case codes.OK:
-		return http.StatusOK
-	case codes.InvalidArgument:
-		return http.StatusBadRequest
-	case codes.Unauthenticated:
-		return http.StatusUnauthorized
-	case codes.PermissionDenied:
-		return http.StatusForbidden
-	case codes.NotFound:
-		return http.StatusNotFound
-	case codes.AlreadyExists:
-		return http.StatusConflict
-	case codes.Internal:
-		return http.StatusInternalServerError
-	case codes.Unavailable:
-		return http.StatusServiceUnavailable
-	default:
-		return http.StatusInternalServerError
+	if c, ok := grpcCodes[code]; ok {
+		return c.status
 	}
+
+	return http.StatusInternalServerError
+}
+
+// GRPCCodeName returns the canonical name of code, e.g. "NOT_FOUND", as used
+// in the "code" field of error responses.
+func GRPCCodeName(code codes.Code) string {
+	if c, ok := grpcCodes[code]; ok {
+		return c.name
+	}
+
+	return grpcCodes[codes.Unknown].name
 }
 
 func HTTPCodeToMessage(code int) string {
@@ -127,10 +245,20 @@ func HTTPCodeToMessage(code int) string {
 		return constant.MessageNotFound
 	case http.StatusConflict:
 		return constant.MessageConflict
+	case http.StatusRequestEntityTooLarge:
+		return constant.MessageRequestEntityTooLarge
+	case http.StatusTooManyRequests:
+		return constant.MessageTooManyRequests
+	case constant.StatusClientClosedRequest:
+		return constant.MessageClientClosedRequest
 	case http.StatusInternalServerError:
 		return constant.MessageInternalServerError
+	case http.StatusNotImplemented:
+		return constant.MessageNotImplemented
 	case http.StatusServiceUnavailable:
 		return constant.MessageServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return constant.MessageGatewayTimeout
 	default:
 		return constant.MessageInternalServerError
 	}
@@ -159,3 +287,43 @@ func GetEnvDurationSeconds(key string, defaultVal time.Duration) time.Duration {
 
 	return defaultVal * time.Second
 }
+
+func GetEnvDurationMilliseconds(key string, defaultVal time.Duration) time.Duration {
+	if val, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return time.Duration(val) * time.Millisecond
+	}
+
+	return defaultVal * time.Millisecond
+}
+
+func GetEnvSlice(key string, defaultVal []string) []string {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+
+	items := []string{}
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func GetEnvBool(key string, defaultVal bool) bool {
+	if val, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return val
+	}
+
+	return defaultVal
+}
+
+func GetEnvFloat(key string, defaultVal float64) float64 {
+	if val, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return val
+	}
+
+	return defaultVal
+}
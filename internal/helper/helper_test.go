@@ -1,8 +1,11 @@
 package helper_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"path"
 	"path/filepath"
 	"runtime"
@@ -15,8 +18,11 @@ import (
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type DummyInput struct {
@@ -51,17 +57,24 @@ func TestPrepareResponse(t *testing.T) {
 
 func TestValidationErrorByTag(t *testing.T) {
 	tests := []struct {
+		locale   string
 		tag      string
 		field    string
 		expected string
 	}{
-		{"required", "email", "email is required"},
-		{"email", "email", "email must be an email"},
-		{"unknown", "field", ""},
+		{"en", "required", "email", "email is required"},
+		{"en", "email", "email", "email must be an email"},
+		{"en", "min", "page", "page is too small"},
+		{"en", "max", "limit", "limit is too large"},
+		{"en", "oneof", "sort_by", "sort_by is not a supported value"},
+		{"en", "uuid", "video_id", "video_id must be a valid UUID"},
+		{"en", "unknown", "field", "field is invalid"},
+		{"es", "required", "email", "email es obligatorio"},
+		{"fr", "unknown", "field", "field n'est pas valide"},
 	}
 
 	for _, tt := range tests {
-		got := helper.ValidationErrorByTag(tt.tag, tt.field)
+		got := helper.ValidationErrorByTag(tt.locale, tt.tag, tt.field)
 		assert.Equal(t, tt.expected, got)
 	}
 }
@@ -72,7 +85,7 @@ func TestPrepareResponseFromValidationError(t *testing.T) {
 	validationErr := validate.Struct(input)
 	validationObj := &DummyValidationError{}
 
-	res := helper.PrepareResponseFromValidationError(validationErr, validationObj)
+	res := helper.PrepareResponseFromValidationError(context.Background(), validationErr, input, validationObj)
 	data := res["data"].(gin.H)
 	errorMap := data["errors"].(map[string][]string)
 	assert.Contains(t, errorMap, "name")
@@ -80,6 +93,27 @@ func TestPrepareResponseFromValidationError(t *testing.T) {
 	assert.Equal(t, constant.MessageBadRequest, res["message"])
 }
 
+func TestPrepareResponseFromQueryBindError(t *testing.T) {
+	type queryInput struct {
+		Page  int    `form:"page"`
+		Query string `form:"query"`
+	}
+	type queryValidationError struct {
+		Page  []string `json:"page"`
+		Query []string `json:"query"`
+	}
+
+	query := url.Values{"page": {"abc"}, "query": {"title"}}
+
+	res := helper.PrepareResponseFromQueryBindError(context.Background(), errors.New("strconv.ParseInt: invalid syntax"), query, &queryInput{}, &queryValidationError{})
+
+	assert.Equal(t, constant.MessageBadRequest, res["message"])
+	assert.Equal(t, map[string][]string{
+		"page":  {"page is invalid"},
+		"query": {},
+	}, res["data"].(gin.H)["errors"])
+}
+
 func TestPrepareResponseFromGRPCError(t *testing.T) {
 	// BAD REQUEST with JSON error
 	validationErrors := &DummyValidationError{"invalid name", "invalid email"}
@@ -101,24 +135,126 @@ func TestPrepareResponseFromGRPCError(t *testing.T) {
 	assert.Empty(t, res["data"].(gin.H))
 }
 
+func TestPrepareResponseFromGRPCError_Details(t *testing.T) {
+	type fieldErrors struct {
+		Name  []string `json:"name"`
+		Email []string `json:"email"`
+	}
+
+	withDetails := func(code codes.Code, details ...protoadapt.MessageV1) error {
+		st, err := status.New(code, "backend message").WithDetails(details...)
+		require.NoError(t, err)
+		return st.Err()
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		obj            any
+		expectedStatus int
+		expected       gin.H
+	}{
+		{
+			name: "bad request field violations",
+			err: withDetails(codes.InvalidArgument, &errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{Field: "email", Description: "email must be an email"},
+					{Field: "email", Description: "email is taken"},
+				},
+			}),
+			obj:            &fieldErrors{},
+			expectedStatus: http.StatusBadRequest,
+			expected: gin.H{
+				"message": constant.MessageBadRequest,
+				"code":    "INVALID_ARGUMENT",
+				"data": gin.H{"errors": map[string][]string{
+					"email": {"email must be an email", "email is taken"},
+					"name":  {},
+				}},
+			},
+		},
+		{
+			name:           "retry info",
+			err:            withDetails(codes.ResourceExhausted, &errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)}),
+			obj:            gin.H{},
+			expectedStatus: http.StatusTooManyRequests,
+			expected: gin.H{
+				"message": constant.MessageTooManyRequests,
+				"code":    "RESOURCE_EXHAUSTED",
+				"data":    gin.H{"retry_after": 2},
+			},
+		},
+		{
+			name: "error info and localized message",
+			err: withDetails(codes.FailedPrecondition,
+				&errdetails.ErrorInfo{Reason: "VIDEO_NOT_PUBLISHED", Domain: "video-catalog", Metadata: map[string]string{"video_id": "1"}},
+				&errdetails.LocalizedMessage{Locale: "en-US", Message: "The video is not published yet"},
+			),
+			obj:            gin.H{},
+			expectedStatus: http.StatusBadRequest,
+			expected: gin.H{
+				"message": "The video is not published yet",
+				"code":    "FAILED_PRECONDITION",
+				"data": gin.H{
+					"reason":   "VIDEO_NOT_PUBLISHED",
+					"domain":   "video-catalog",
+					"metadata": map[string]string{"video_id": "1"},
+					"errors":   gin.H{},
+				},
+			},
+		},
+		{
+			name:           "context error",
+			err:            context.DeadlineExceeded,
+			obj:            gin.H{},
+			expectedStatus: http.StatusGatewayTimeout,
+			expected: gin.H{
+				"message": constant.MessageGatewayTimeout,
+				"code":    "DEADLINE_EXCEEDED",
+				"data":    gin.H{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, res := helper.PrepareResponseFromGRPCError(tt.err, tt.obj)
+
+			assert.Equal(t, tt.expectedStatus, code)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
 func TestGRPCToHttpCode(t *testing.T) {
 	tests := []struct {
-		code     codes.Code
-		expected int
+		code         codes.Code
+		expected     int
+		expectedName string
 	}{
-		{codes.OK, http.StatusOK},
-		{codes.InvalidArgument, http.StatusBadRequest},
-		{codes.Unauthenticated, http.StatusUnauthorized},
-		{codes.PermissionDenied, http.StatusForbidden},
-		{codes.NotFound, http.StatusNotFound},
-		{codes.AlreadyExists, http.StatusConflict},
-		{codes.Internal, http.StatusInternalServerError},
-		{codes.Unavailable, http.StatusServiceUnavailable},
-		{codes.Unknown, http.StatusInternalServerError}, // fallback
+		{codes.OK, http.StatusOK, "OK"},
+		{codes.Canceled, constant.StatusClientClosedRequest, "CANCELLED"},
+		{codes.Unknown, http.StatusInternalServerError, "UNKNOWN"},
+		{codes.InvalidArgument, http.StatusBadRequest, "INVALID_ARGUMENT"},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout, "DEADLINE_EXCEEDED"},
+		{codes.NotFound, http.StatusNotFound, "NOT_FOUND"},
+		{codes.AlreadyExists, http.StatusConflict, "ALREADY_EXISTS"},
+		{codes.PermissionDenied, http.StatusForbidden, "PERMISSION_DENIED"},
+		{codes.ResourceExhausted, http.StatusTooManyRequests, "RESOURCE_EXHAUSTED"},
+		{codes.FailedPrecondition, http.StatusBadRequest, "FAILED_PRECONDITION"},
+		{codes.Aborted, http.StatusConflict, "ABORTED"},
+		{codes.OutOfRange, http.StatusBadRequest, "OUT_OF_RANGE"},
+		{codes.Unimplemented, http.StatusNotImplemented, "UNIMPLEMENTED"},
+		{codes.Internal, http.StatusInternalServerError, "INTERNAL"},
+		{codes.Unavailable, http.StatusServiceUnavailable, "UNAVAILABLE"},
+		{codes.DataLoss, http.StatusInternalServerError, "DATA_LOSS"},
+		{codes.Unauthenticated, http.StatusUnauthorized, "UNAUTHENTICATED"},
+		{codes.Code(42), http.StatusInternalServerError, "UNKNOWN"}, // fallback
 	}
 
 	for _, tt := range tests {
 		assert.Equal(t, tt.expected, helper.GRPCToHttpCode(tt.code))
+		assert.Equal(t, tt.expectedName, helper.GRPCCodeName(tt.code))
 	}
 }
 
@@ -133,8 +269,13 @@ func TestHTTPCodeToMessage(t *testing.T) {
 		{http.StatusForbidden, constant.MessageForbidden},
 		{http.StatusNotFound, constant.MessageNotFound},
 		{http.StatusConflict, constant.MessageConflict},
+		{http.StatusRequestEntityTooLarge, constant.MessageRequestEntityTooLarge},
+		{http.StatusTooManyRequests, constant.MessageTooManyRequests},
+		{constant.StatusClientClosedRequest, constant.MessageClientClosedRequest},
 		{http.StatusInternalServerError, constant.MessageInternalServerError},
+		{http.StatusNotImplemented, constant.MessageNotImplemented},
 		{http.StatusServiceUnavailable, constant.MessageServiceUnavailable},
+		{http.StatusGatewayTimeout, constant.MessageGatewayTimeout},
 		{418, constant.MessageInternalServerError}, // unknown/fallback
 	}
 
@@ -261,3 +402,164 @@ func TestGetEnvDurationSeconds(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEnvBool(t *testing.T) {
+	tests := []struct {
+		name       string
+		envKey     string
+		envValue   string
+		defaultVal bool
+		expected   bool
+	}{
+		{
+			name:       "valid bool from env",
+			envKey:     "TEST_ENV_BOOL",
+			envValue:   "false",
+			defaultVal: true,
+			expected:   false,
+		},
+		{
+			name:       "invalid bool from env, fallback to default",
+			envKey:     "TEST_ENV_BOOL_INVALID",
+			envValue:   "abc",
+			defaultVal: true,
+			expected:   true,
+		},
+		{
+			name:       "env not set, fallback to default",
+			envKey:     "TEST_ENV_BOOL_NOT_SET",
+			envValue:   "",
+			defaultVal: false,
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envValue != "" {
+				t.Setenv(tt.envKey, tt.envValue)
+			}
+			got := helper.GetEnvBool(tt.envKey, tt.defaultVal)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestGetEnvFloat(t *testing.T) {
+	tests := []struct {
+		name       string
+		envKey     string
+		envValue   string
+		defaultVal float64
+		expected   float64
+	}{
+		{
+			name:       "valid float from env",
+			envKey:     "TEST_ENV_FLOAT",
+			envValue:   "0.25",
+			defaultVal: 0.5,
+			expected:   0.25,
+		},
+		{
+			name:       "invalid float from env, fallback to default",
+			envKey:     "TEST_ENV_FLOAT_INVALID",
+			envValue:   "abc",
+			defaultVal: 0.5,
+			expected:   0.5,
+		},
+		{
+			name:       "env not set, fallback to default",
+			envKey:     "TEST_ENV_FLOAT_NOT_SET",
+			envValue:   "",
+			defaultVal: 0.5,
+			expected:   0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envValue != "" {
+				t.Setenv(tt.envKey, tt.envValue)
+			}
+			got := helper.GetEnvFloat(tt.envKey, tt.defaultVal)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestGetEnvDurationMilliseconds(t *testing.T) {
+	tests := []struct {
+		name       string
+		envKey     string
+		envValue   string
+		defaultVal time.Duration
+		expected   time.Duration
+	}{
+		{
+			name:       "valid duration from env",
+			envKey:     "TEST_ENV_DURATION_MS",
+			envValue:   "250",
+			defaultVal: 100,
+			expected:   250 * time.Millisecond,
+		},
+		{
+			name:       "invalid duration from env, fallback",
+			envKey:     "TEST_ENV_DURATION_MS_INVALID",
+			envValue:   "abc",
+			defaultVal: 100,
+			expected:   100 * time.Millisecond,
+		},
+		{
+			name:       "env not set, fallback",
+			envKey:     "TEST_ENV_DURATION_MS_NOT_SET",
+			envValue:   "",
+			defaultVal: 100,
+			expected:   100 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envValue != "" {
+				t.Setenv(tt.envKey, tt.envValue)
+			}
+			got := helper.GetEnvDurationMilliseconds(tt.envKey, tt.defaultVal)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestGetEnvSlice(t *testing.T) {
+	tests := []struct {
+		name       string
+		envKey     string
+		envValue   string
+		defaultVal []string
+		expected   []string
+	}{
+		{
+			name:       "comma separated values from env",
+			envKey:     "TEST_ENV_SLICE",
+			envValue:   "a, b,,c ",
+			defaultVal: []string{"default"},
+			expected:   []string{"a", "b", "c"},
+		},
+		{
+			name:       "env not set, fallback",
+			envKey:     "TEST_ENV_SLICE_NOT_SET",
+			envValue:   "",
+			defaultVal: []string{"default"},
+			expected:   []string{"default"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envValue != "" {
+				t.Setenv(tt.envKey, tt.envValue)
+			}
+			got := helper.GetEnvSlice(tt.envKey, tt.defaultVal)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
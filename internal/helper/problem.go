@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+)
+
+const ContentTypeProblemJSON = "application/problem+json"
+
+// Respond writes a response built by PrepareResponse with its message in the
+// locale of the request.
+func Respond(c *gin.Context, status int, res gin.H) {
+	c.JSON(status, localize(c, res))
+}
+
+// RespondWithError aborts the request with an error response built by one of
+// the Prepare* functions, its message in the locale of the request. It is
+// written as is, or as an RFC 9457 problem document when
+// ProblemDetailsMiddleware picked that format for the request.
+func RespondWithError(c *gin.Context, status int, res gin.H) {
+	res = localize(c, res)
+
+	if !c.GetBool(constant.ProblemDetails) {
+		c.AbortWithStatusJSON(status, res)
+		return
+	}
+
+	c.Header("Content-Type", ContentTypeProblemJSON)
+	c.AbortWithStatusJSON(status, NewProblem(c, status, res))
+}
+
+// NewProblem turns an error response into a problem document. The message is
+// the detail, fields of data such as "errors" or "retry_after" become
+// extension members next to the request id and gRPC code.
+func NewProblem(c *gin.Context, status int, res gin.H) gin.H {
+	title := http.StatusText(status)
+	if title == "" {
+		title = HTTPCodeToMessage(status)
+	}
+
+	problem := gin.H{}
+	if data, ok := res["data"].(gin.H); ok {
+		for k, v := range data {
+			problem[k] = v
+		}
+	}
+
+	problem["type"] = "about:blank"
+	problem["title"] = i18n.T(i18n.FromContext(c.Request.Context()), title)
+	problem["status"] = status
+	problem["instance"] = c.Request.URL.Path
+	if message, ok := res["message"].(string); ok && message != "" {
+		problem["detail"] = message
+	}
+	if code, ok := res["code"]; ok {
+		problem["code"] = code
+	}
+	if id := requestid.FromContext(c.Request.Context()); id != "" {
+		problem["request_id"] = id
+	}
+
+	return problem
+}
+
+// localize returns res with its message translated, res itself is not
+// modified.
+func localize(c *gin.Context, res gin.H) gin.H {
+	message, ok := res["message"].(string)
+	if !ok {
+		return res
+	}
+
+	localized := make(gin.H, len(res))
+	for k, v := range res {
+		localized[k] = v
+	}
+	localized["message"] = i18n.T(i18n.FromContext(c.Request.Context()), message)
+
+	return localized
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/openapi"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/route"
+)
+
+const openAPIVersion = "1.0.0"
+
+var (
+	healthStatus = openapi.Object(map[string]*openapi.Schema{
+		"status": {Type: "string", Enum: []any{constant.HealthStatusHealthy, constant.HealthStatusDegraded, constant.HealthStatusUnhealthy, constant.HealthStatusShuttingDown}},
+	}, "status")
+
+	healthReport = openapi.Object(map[string]*openapi.Schema{
+		"status": healthStatus.Properties["status"],
+		"services": {Type: "object", AdditionalProperties: openapi.Object(map[string]*openapi.Schema{
+			"status":     openapi.String(),
+			"critical":   openapi.Boolean(),
+			"latency_ms": openapi.Integer(),
+			"error":      openapi.String(),
+			"checked_at": {Type: "string", Format: "date-time"},
+			"since":      {Type: "string", Format: "date-time"},
+		})},
+	}, "status", "services")
+)
+
+// operation describes the handler of b bound to rt.
+func (b handlerBinding) operation(rt route.Route) openapi.Operation {
+	op := openapi.Bound(b.rpc, b.input, rt.Method, rt.Path)
+
+	doc := b.doc
+	if doc.Summary != "" {
+		op.Summary = doc.Summary
+	}
+	if doc.Response != nil {
+		op.Response = doc.Response
+	}
+	op.Description, op.Status, op.ContentType, op.Responses = doc.Description, doc.Status, doc.ContentType, doc.Responses
+	if b.errors != nil {
+		op.Errors = openapi.FieldErrors(b.errors)
+	}
+
+	return op
+}
+
+// operationFor describes the handler of rt, ok is false when neither
+// handlerBindings nor the transcoder know it.
+func operationFor(cfg RouterConfig, bindings map[string]handlerBinding, rt route.Route) (openapi.Operation, bool) {
+	if b, ok := bindings[rt.Binding()]; ok {
+		return b.operation(rt), true
+	}
+
+	if cfg.Transcoder != nil {
+		if method, err := cfg.Transcoder.Method(rt.Service, rt.RPC); err == nil {
+			return openapi.RPC(method, rt.Method, rt.Path), true
+		}
+	}
+
+	return openapi.Operation{Summary: rt.Binding()}, false
+}
+
+// UndocumentedRoutes returns the routes of table that NewOpenAPIDocument can
+// only list without describing their input and output.
+func UndocumentedRoutes(cfg RouterConfig, table *route.Table) []route.Route {
+	bindings := handlerBindings()
+
+	var undocumented []route.Route
+	for _, rt := range table.Routes {
+		if _, ok := operationFor(cfg, bindings, rt); !ok {
+			undocumented = append(undocumented, rt)
+		}
+	}
+
+	return undocumented
+}
+
+// NewOpenAPIDocument describes the routes of table as registered by
+// NewRouter.
+func NewOpenAPIDocument(cfg RouterConfig, table *route.Table) *openapi.Document {
+	for _, rt := range UndocumentedRoutes(cfg, table) {
+		logger.Warn().Str("route", rt.String()).Msg("Route has no OpenAPI operation")
+	}
+
+	bindings := handlerBindings()
+	routes := make([]openapi.Route, 0, len(table.Routes))
+	for _, rt := range table.Routes {
+		op, _ := operationFor(cfg, bindings, rt)
+		routes = append(routes, openapi.Route{
+			Method:    rt.Method,
+			Path:      rt.Path,
+			Service:   rt.Service,
+			RPC:       rt.RPC,
+			Auth:      rt.Auth,
+			RateLimit: len(rt.RateLimits) > 0,
+			Backend:   rt.Service != constant.ServiceGateway,
+			Operation: op,
+		})
+	}
+
+	return openapi.Generate(openapi.Info{
+		Title:       constant.ServiceName,
+		Description: "HTTP API of the video streaming services.",
+		Version:     openAPIVersion,
+	}, routes)
+}
+
+// openAPIHandler serves doc, it is encoded once.
+func openAPIHandler(doc *openapi.Document) gin.HandlerFunc {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to encode the OpenAPI document")
+	}
+
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// registerDocs serves the docs UI at /docs for the OpenAPI route of table.
+func registerDocs(r *gin.Engine, table *route.Table) {
+	for _, rt := range table.Routes {
+		if rt.Binding() == constant.ServiceGateway+".OpenAPI" {
+			r.GET("/docs", gin.WrapH(openapi.DocsHandler(rt.Path)))
+			return
+		}
+	}
+
+	logger.Warn().Msg("OpenAPI docs are enabled but no route serves the OpenAPI document")
+}
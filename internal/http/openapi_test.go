@@ -0,0 +1,163 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	myhttp "github.com/sagarmaheshwary/microservices-api-gateway/internal/http"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/openapi"
+	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
+	uploadpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/upload/upload"
+	videocatalogpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/video_catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/route"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// A route added to routes.yaml must have a handler binding, or be served by
+// the transcoder, before it ships.
+func TestOpenAPI_CoversEveryRoute(t *testing.T) {
+	table, err := route.Default()
+	require.NoError(t, err)
+
+	for _, rt := range myhttp.UndocumentedRoutes(myhttp.RouterConfig{}, table) {
+		t.Errorf("route %s has no OpenAPI operation", rt)
+	}
+
+	doc := myhttp.NewOpenAPIDocument(myhttp.RouterConfig{}, table)
+	for _, rt := range table.Routes {
+		path := rt.Path
+		for _, segment := range strings.Split(rt.Path, "/") {
+			if strings.HasPrefix(segment, ":") {
+				path = strings.Replace(path, segment, "{"+segment[1:]+"}", 1)
+			}
+		}
+
+		item, ok := doc.Paths[path]
+		if assert.True(t, ok, "missing path %s", path) {
+			assert.Contains(t, item, strings.ToLower(rt.Method), "missing operation %s", rt)
+		}
+	}
+}
+
+// The operations of the handler bound routes describe what the handlers in
+// internal/handler bind and answer with.
+func TestOpenAPI_HandlerSchemas(t *testing.T) {
+	table, err := route.Default()
+	require.NoError(t, err)
+	doc := myhttp.NewOpenAPIDocument(myhttp.RouterConfig{}, table)
+
+	tests := []struct {
+		method   string
+		path     string
+		body     any
+		query    any
+		errors   any
+		status   string
+		response proto.Message
+	}{
+		{method: "post", path: "/auth/register", body: &types.RegisterInput{}, errors: &types.RegisterValidationError{}, status: "201", response: &authpb.RegisterResponse{}},
+		{method: "post", path: "/auth/login", body: &types.LoginInput{}, errors: &types.LoginValidationError{}, status: "200", response: &authpb.LoginResponse{}},
+		{method: "post", path: "/auth/logout", status: "200", response: &authpb.LogoutResponse{}},
+		{method: "post", path: "/videos/upload/presigned-url", status: "200", response: &uploadpb.CreatePresignedUrlResponse{}},
+		{method: "post", path: "/videos/upload/webhook", body: &types.UploadedWebhookInput{}, errors: &types.UploadedWebhookValidationError{}, status: "200", response: &uploadpb.UploadedWebhookResponse{}},
+		{method: "get", path: "/videos", query: &types.FindAllVideosInput{}, errors: &types.FindAllVideosValidationError{}, status: "200", response: &videocatalogpb.FindAllResponse{}},
+		{method: "get", path: "/videos/{id}", status: "200", response: &videocatalogpb.FindByIdResponse{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			op := doc.Paths[tt.path][tt.method]
+			require.NotNil(t, op)
+
+			if tt.body != nil {
+				require.NotNil(t, op.RequestBody)
+				assert.Equal(t, componentRef(reflect.TypeOf(tt.body).Elem().Name()), op.RequestBody.Content["application/json"].Schema.Ref)
+			} else {
+				assert.Nil(t, op.RequestBody)
+			}
+
+			var query, expectedQuery []string
+			for _, p := range op.Parameters {
+				if p.In == "query" {
+					query = append(query, p.Name)
+				}
+			}
+			if tt.query != nil {
+				for _, p := range openapi.Parameters("query", tt.query) {
+					expectedQuery = append(expectedQuery, p.Name)
+				}
+			}
+			assert.Equal(t, expectedQuery, query)
+
+			if tt.errors != nil {
+				b, err := json.Marshal(op.Responses["400"])
+				require.NoError(t, err)
+				assert.Contains(t, string(b), componentRef(reflect.TypeOf(tt.errors).Elem().Name()))
+			}
+
+			require.Contains(t, op.Responses, tt.status)
+			assert.Equal(t, componentRef(string(tt.response.ProtoReflect().Descriptor().FullName())), op.Responses[tt.status].Content["application/json"].Schema.Ref)
+		})
+	}
+}
+
+func componentRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+func TestNewRouter_OpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name               string
+		openAPIDocs        bool
+		expectedDocsStatus int
+	}{
+		{name: "docs disabled", openAPIDocs: false, expectedDocsStatus: http.StatusNotFound},
+		{name: "docs enabled", openAPIDocs: true, expectedDocsStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := myhttp.NewRouter(myhttp.RouterConfig{
+				Env:                 "test",
+				AuthHandler:         new(mockAuthHandler),
+				HealthHandler:       new(mockHealthHandler),
+				UploadHandler:       new(mockUploadHandler),
+				VideoCatalogHandler: new(mockVideoCatalogHandler),
+				OpenAPIDocs:         tt.openAPIDocs,
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+			var doc struct {
+				OpenAPI string                    `json:"openapi"`
+				Paths   map[string]map[string]any `json:"paths"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+			assert.Equal(t, "3.1.0", doc.OpenAPI)
+			assert.Contains(t, doc.Paths["/auth/register"], "post")
+			assert.Contains(t, doc.Paths["/videos/{id}"], "get")
+
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+			assert.Equal(t, tt.expectedDocsStatus, w.Code)
+			if tt.openAPIDocs {
+				assert.Contains(t, w.Body.String(), `data-spec-url="/openapi.json"`)
+			}
+		})
+	}
+}
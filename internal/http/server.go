@@ -1,18 +1,37 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/handler"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/health"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/jwt"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/openapi"
+	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
+	uploadpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/upload/upload"
+	videocatalogpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/video_catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/ratelimit"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/responsecache"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/route"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/transcoder"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 type AuthHandler interface {
@@ -23,6 +42,8 @@ type AuthHandler interface {
 }
 
 type HealthHandler interface {
+	Live(*gin.Context)
+	Ready(*gin.Context)
 	CheckAll(*gin.Context)
 }
 
@@ -44,6 +65,29 @@ type RouterConfig struct {
 	VideoCatalogHandler VideoCatalogHandler
 	VerifyToken         middleware.VerifyTokenFunc
 	Middlewares         []gin.HandlerFunc
+	RateLimit           *config.RateLimit
+	RateLimitStore      ratelimit.Store
+	// Routes defaults to the route table embedded in internal/route.
+	Routes *route.Table
+	// Transcoder serves routes that have no handler bound to them.
+	Transcoder *transcoder.Transcoder
+	// ResponseCache caches public routes with a cache policy, nil disables it.
+	ResponseCache responsecache.Store
+	// BackendReady gates the routes of a service until its backend is ready,
+	// see types.GRPCClients.Ready.
+	BackendReady map[string]func() bool
+	// ProblemDetails writes errors as application/problem+json unless the
+	// client asks for application/json, otherwise only clients accepting
+	// problem+json get it.
+	ProblemDetails bool
+	// DefaultLocale is the locale of clients without a supported
+	// Accept-Language, it defaults to i18n.DefaultLocale.
+	DefaultLocale string
+	// OpenAPIDocs serves a docs UI for the OpenAPI document at /docs.
+	OpenAPIDocs bool
+	// TrustedProxies may set the client IP through X-Forwarded-For, nil
+	// trusts none.
+	TrustedProxies []string
 }
 
 func NewRouter(cfg RouterConfig) *gin.Engine {
@@ -57,73 +101,540 @@ func NewRouter(cfg RouterConfig) *gin.Engine {
 	}
 
 	r := gin.New()
+	// gin trusts every proxy by default, clients could then pick their IP and
+	// a fresh rate limit with each request
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Fatal().Err(err).Msg("Invalid trusted proxies")
+	}
+	if cfg.RateLimit != nil && cfg.RateLimit.Enabled {
+		if err := cfg.RateLimit.Validate(); err != nil {
+			logger.Fatal().Err(err).Msg("Invalid rate limit config")
+		}
+	}
 
 	// Default middleware if not overridden
 	if len(cfg.Middlewares) == 0 {
 		cfg.Middlewares = []gin.HandlerFunc{
 			gin.Recovery(),
-			middleware.ZerologMiddleware(),
+			// tracing and the request id come first so the request log has both
 			otelgin.Middleware(constant.ServiceName),
+			middleware.RequestIDMiddleware(),
+			middleware.ZerologMiddleware(),
 			middleware.PrometheusMiddleware(),
 			middleware.CORSMiddleware(),
 		}
 	}
 
 	r.Use(cfg.Middlewares...)
+	if cfg.DefaultLocale == "" {
+		cfg.DefaultLocale = i18n.DefaultLocale
+	}
+	r.Use(middleware.ProblemDetailsMiddleware(cfg.ProblemDetails), middleware.LocaleMiddleware(cfg.DefaultLocale))
+	r.NoRoute(func(c *gin.Context) {
+		helper.RespondWithError(c, http.StatusNotFound, helper.PrepareResponse(constant.MessageNotFound, gin.H{}))
+	})
+
+	routes := cfg.Routes
+	if routes == nil {
+		var err error
+		if routes, err = route.Default(); err != nil {
+			logger.Fatal().Err(err).Msg("Invalid default route table")
+		}
+	}
+
+	if err := registerRoutes(r, cfg, routes); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to register routes")
+	}
+
+	if cfg.OpenAPIDocs {
+		registerDocs(r, routes)
+	}
+
+	return r
+}
 
-	r.GET("/health", cfg.HealthHandler.CheckAll)
-	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+// handlerBinding is the handler bound to the "<service>.<rpc>" of routes,
+// with what the OpenAPI document says about it. Routes without a binding are
+// served and described by the transcoder.
+type handlerBinding struct {
+	// handler returns the handler, nil when cfg has none.
+	handler func(cfg RouterConfig, table *route.Table) gin.HandlerFunc
+	// rpc is the backend method the handler calls, its output is the response.
+	rpc protoreflect.MethodDescriptor
+	// input is what the handler binds from the request, e.g.
+	// &types.LoginInput{}, and errors the field errors of invalid input.
+	input  any
+	errors any
+	// doc describes the rest of the operation, e.g. its summary or the
+	// response of handlers without rpc.
+	doc openapi.Operation
+}
+
+// handlerBindings maps the "<service>.<rpc>" of a route to its handler.
+func handlerBindings() map[string]handlerBinding {
+	auth := authpb.AuthenticationService_ServiceDesc.ServiceName
+	upload := uploadpb.UploadService_ServiceDesc.ServiceName
+	videoCatalog := videocatalogpb.VideoCatalogService_ServiceDesc.ServiceName
+
+	return map[string]handlerBinding{
+		constant.ServiceGateway + ".Health": {
+			handler: bindHandler(healthHandler, HealthHandler.CheckAll),
+			doc: openapi.Operation{
+				Summary:   "Status of every backend",
+				Response:  openapi.Envelope(healthReport),
+				Responses: map[int]*openapi.Schema{http.StatusServiceUnavailable: openapi.Envelope(healthReport)},
+			},
+		},
+		constant.ServiceGateway + ".Live": {
+			handler: bindHandler(healthHandler, HealthHandler.Live),
+			doc:     openapi.Operation{Summary: "Liveness probe", Response: openapi.Envelope(healthStatus)},
+		},
+		constant.ServiceGateway + ".Ready": {
+			handler: bindHandler(healthHandler, HealthHandler.Ready),
+			doc: openapi.Operation{
+				Summary:   "Readiness probe",
+				Response:  openapi.Envelope(healthStatus),
+				Responses: map[int]*openapi.Schema{http.StatusServiceUnavailable: openapi.Envelope(healthStatus)},
+			},
+		},
+		constant.ServiceGateway + ".Metrics": {
+			handler: func(RouterConfig, *route.Table) gin.HandlerFunc { return gin.WrapH(promhttp.Handler()) },
+			doc:     openapi.Operation{Summary: "Prometheus metrics", Response: openapi.String(), ContentType: "text/plain"},
+		},
+		constant.ServiceGateway + ".OpenAPI": {
+			handler: func(cfg RouterConfig, table *route.Table) gin.HandlerFunc {
+				return openAPIHandler(NewOpenAPIDocument(cfg, table))
+			},
+			doc: openapi.Operation{Summary: "This OpenAPI document", Response: &openapi.Schema{Type: "object"}},
+		},
+		constant.ServiceAuthentication + ".Register": {
+			handler: bindHandler(authHandler, AuthHandler.Register),
+			rpc:     rpcMethod(auth, "Register"),
+			input:   &types.RegisterInput{},
+			errors:  &types.RegisterValidationError{},
+			doc:     openapi.Operation{Summary: "Register a user", Status: http.StatusCreated},
+		},
+		constant.ServiceAuthentication + ".Login": {
+			handler: bindHandler(authHandler, AuthHandler.Login),
+			rpc:     rpcMethod(auth, "Login"),
+			input:   &types.LoginInput{},
+			errors:  &types.LoginValidationError{},
+			doc:     openapi.Operation{Summary: "Log in with email and password"},
+		},
+		constant.ServiceAuthentication + ".Profile": {
+			handler: bindHandler(authHandler, AuthHandler.Profile),
+			doc: openapi.Operation{
+				Summary:  "The authenticated user",
+				Response: openapi.Envelope(openapi.Object(map[string]*openapi.Schema{"user": openapi.Message(&authpb.User{})}, "user")),
+			},
+		},
+		constant.ServiceAuthentication + ".Logout": {
+			handler: bindHandler(authHandler, AuthHandler.Logout),
+			rpc:     rpcMethod(auth, "Logout"),
+			doc:     openapi.Operation{Summary: "Revoke the bearer token"},
+		},
+		constant.ServiceUpload + ".CreatePresignedUrl": {
+			handler: bindHandler(uploadHandler, UploadHandler.CreatePresignedUrl),
+			rpc:     rpcMethod(upload, "CreatePresignedUrl"),
+			doc:     openapi.Operation{Summary: "URLs to upload a video and its thumbnail to"},
+		},
+		constant.ServiceUpload + ".UploadedWebhook": {
+			handler: bindHandler(uploadHandler, UploadHandler.UploadedWebhook),
+			rpc:     rpcMethod(upload, "UploadedWebhook"),
+			input:   &types.UploadedWebhookInput{},
+			errors:  &types.UploadedWebhookValidationError{},
+			doc:     openapi.Operation{Summary: "Publish an uploaded video"},
+		},
+		constant.ServiceVideoCatalog + ".FindAll": {
+			handler: bindHandler(videoCatalogHandler, VideoCatalogHandler.FindAll),
+			rpc:     rpcMethod(videoCatalog, "FindAll"),
+			input:   &types.FindAllVideosInput{},
+			errors:  &types.FindAllVideosValidationError{},
+			doc: openapi.Operation{
+				Summary:     "List videos",
+				Description: "Pages through videos with page or, for large lists, with the next_cursor of the previous page.",
+			},
+		},
+		constant.ServiceVideoCatalog + ".FindById": {
+			handler: bindHandler(videoCatalogHandler, VideoCatalogHandler.FindById),
+			rpc:     rpcMethod(videoCatalog, "FindById"),
+			doc:     openapi.Operation{Summary: "A video and its streaming manifest"},
+		},
+	}
+}
 
-	auth := r.Group("/auth")
-	{
-		auth.POST("/register", cfg.AuthHandler.Register)
-		auth.POST("/login", cfg.AuthHandler.Login)
+func healthHandler(cfg RouterConfig) HealthHandler             { return cfg.HealthHandler }
+func authHandler(cfg RouterConfig) AuthHandler                 { return cfg.AuthHandler }
+func uploadHandler(cfg RouterConfig) UploadHandler             { return cfg.UploadHandler }
+func videoCatalogHandler(cfg RouterConfig) VideoCatalogHandler { return cfg.VideoCatalogHandler }
 
-		authenticated := auth.Group("/", middleware.VerifyTokenMiddleware(cfg.VerifyToken))
-		{
-			authenticated.GET("/profile", cfg.AuthHandler.Profile)
-			authenticated.POST("/logout", cfg.AuthHandler.Logout)
+// bindHandler binds method of the handler get returns, e.g.
+// bindHandler(authHandler, AuthHandler.Login). The binding has no handler
+// while get returns nil.
+func bindHandler[H any](get func(RouterConfig) H, method func(H, *gin.Context)) func(RouterConfig, *route.Table) gin.HandlerFunc {
+	return func(cfg RouterConfig, _ *route.Table) gin.HandlerFunc {
+		h := get(cfg)
+		if any(h) == nil {
+			return nil
 		}
+
+		return func(c *gin.Context) { method(h, c) }
 	}
+}
 
-	videos := r.Group("/videos")
-	{
-		videos.GET("", cfg.VideoCatalogHandler.FindAll)
-		videos.GET("/:id", cfg.VideoCatalogHandler.FindById)
+// rpcMethod returns the descriptor of rpc of the gRPC service, nil when it is
+// not registered.
+func rpcMethod(service string, rpc string) protoreflect.MethodDescriptor {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service + "." + rpc))
+	if err != nil {
+		return nil
+	}
+	method, _ := desc.(protoreflect.MethodDescriptor)
 
-		authenticated := videos.Group("/", middleware.VerifyTokenMiddleware(cfg.VerifyToken))
-		{
-			authenticated.POST("/upload/presigned-url", cfg.UploadHandler.CreatePresignedUrl)
-			authenticated.POST("/upload/webhook", cfg.UploadHandler.UploadedWebhook)
+	return method
+}
+
+func registerRoutes(r *gin.Engine, cfg RouterConfig, table *route.Table) (err error) {
+	// gin panics on conflicting paths, report it like any other invalid route
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%v", p)
+		}
+	}()
+
+	bindings := handlerBindings()
+
+	var errs []error
+	for i, rt := range table.Routes {
+		var handler gin.HandlerFunc
+		binding, ok := bindings[rt.Binding()]
+		if ok {
+			handler = binding.handler(cfg, table)
+			ok = handler != nil
 		}
+		if !ok && cfg.Transcoder != nil {
+			var err error
+			if handler, err = cfg.Transcoder.Handler(rt.Service, rt.RPC); err != nil {
+				errs = append(errs, fmt.Errorf("routes[%d] %s: %w", i, rt, err))
+				continue
+			}
+		} else if !ok {
+			errs = append(errs, fmt.Errorf("routes[%d] %s: no handler bound to %q", i, rt, rt.Binding()))
+			continue
+		}
+
+		handlers, err := routeMiddlewares(cfg, rt)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("routes[%d] %s: %w", i, rt, err))
+			continue
+		}
+
+		r.Handle(rt.Method, rt.Path, append(handlers, handler)...)
 	}
 
-	return r
+	return errors.Join(errs...)
+}
+
+// routeMiddlewares builds the chain in front of a route's handler. Limits
+// keyed by user run after token verification so they can see the user, the
+// others run first to shed load before the token is checked.
+func routeMiddlewares(cfg RouterConfig, rt route.Route) ([]gin.HandlerFunc, error) {
+	var before, after []gin.HandlerFunc
+
+	if ready, ok := cfg.BackendReady[rt.Service]; ok {
+		before = append(before, middleware.BackendReadyMiddleware(ready))
+	}
+
+	if rt.Timeout > 0 {
+		before = append(before, middleware.TimeoutMiddleware(rt.Timeout))
+	}
+
+	for _, name := range rt.RateLimits {
+		rule, err := rateLimitRule(cfg.RateLimit, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if rt.Auth && rule != nil && rule.KeyBy == constant.RateLimitKeyByUser {
+			after = append(after, rateLimitMiddlewares(cfg, name, rule)...)
+		} else {
+			before = append(before, rateLimitMiddlewares(cfg, name, rule)...)
+		}
+	}
+
+	if rt.Auth {
+		before = append(before, middleware.VerifyTokenMiddleware(cfg.VerifyToken))
+	}
+
+	if rt.Cache != nil {
+		after = append(after, middleware.CacheControlMiddleware(rt.Cache.TTL, rt.Auth))
+
+		// responses of authenticated routes differ per user, they are only
+		// cacheable by the client
+		if cfg.ResponseCache != nil && !rt.Auth {
+			after = append(after, middleware.ResponseCacheMiddleware(cfg.ResponseCache, rt.Cache.TTL, rt.Cache.Tags))
+		}
+	}
+
+	if cfg.ResponseCache != nil && len(rt.Invalidates) > 0 {
+		after = append(after, middleware.InvalidateResponseCacheMiddleware(cfg.ResponseCache, rt.Invalidates))
+	}
+
+	return append(before, after...), nil
+}
+
+func rateLimitRule(cfg *config.RateLimit, name string) (*config.RateLimitRule, error) {
+	if cfg == nil {
+		cfg = &config.RateLimit{}
+	}
+
+	rule, ok := cfg.Rules()[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown rate limit %q", name)
+	}
+
+	return rule, nil
+}
+
+// rateLimitMiddlewares returns the limiter for a route group, or nothing when
+// rate limiting or the group's rule is disabled.
+func rateLimitMiddlewares(cfg RouterConfig, scope string, rule *config.RateLimitRule) []gin.HandlerFunc {
+	if cfg.RateLimit == nil || !cfg.RateLimit.Enabled || cfg.RateLimitStore == nil || rule == nil {
+		return nil
+	}
+
+	algorithm, err := ratelimit.ParseAlgorithm(cfg.RateLimit.Algorithm)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid rate limit config")
+	}
+
+	keyFunc := middleware.RateLimitKeyByIP
+	if rule.KeyBy == constant.RateLimitKeyByUser {
+		keyFunc = middleware.RateLimitKeyByUser
+	}
+
+	limit := ratelimit.Limit{
+		Algorithm: algorithm,
+		Requests:  rule.Requests,
+		Window:    rule.Window,
+	}
+
+	return []gin.HandlerFunc{middleware.RateLimitMiddleware(cfg.RateLimitStore, scope, limit, keyFunc)}
+}
+
+// verifyTokenFunc picks how bearer tokens are checked: by the authentication
+// service, locally against a shared secret or JWKS, or locally with the
+// authentication service as a fallback for tokens the gateway can't verify.
+// Local verification rejects the tokens revoked reports as logged out.
+func verifyTokenFunc(cfg *config.JWT, remote middleware.VerifyTokenFunc, revoked middleware.RevokedFunc) middleware.VerifyTokenFunc {
+	if cfg == nil || cfg.VerifyMode == constant.JWTVerifyModeRemote {
+		return remote
+	}
+
+	if cfg.VerifyMode != constant.JWTVerifyModeLocal && cfg.VerifyMode != constant.JWTVerifyModeLocalWithFallback {
+		logger.Fatal().Str("mode", cfg.VerifyMode).Msg("Invalid JWT verify mode")
+	}
+
+	opts := jwt.Options{
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+		Leeway:   cfg.Leeway,
+	}
+	if cfg.Secret != "" {
+		opts.Secret = []byte(cfg.Secret)
+	}
+	if cfg.JWKSURL != "" {
+		opts.KeySet = jwt.NewJWKS(cfg.JWKSURL, cfg.JWKSRefreshInterval, nil)
+	}
+	if opts.Secret == nil && opts.KeySet == nil {
+		logger.Fatal().Str("mode", cfg.VerifyMode).Msg("JWT verify mode requires JWT_SECRET or JWT_JWKS_URL")
+	}
+
+	var fallback middleware.VerifyTokenFunc
+	if cfg.VerifyMode == constant.JWTVerifyModeLocalWithFallback {
+		fallback = remote
+	}
+
+	if revoked == nil {
+		logger.Warn().Str("mode", cfg.VerifyMode).Msg("Logged out tokens stay valid until they expire, enable the token cache to reject them")
+	}
+
+	return middleware.LocalVerifyToken(jwt.NewVerifier(opts), fallback, revoked)
 }
 
-func NewServer(cfg *config.HTTPServer, env string, grpcClients types.GRPCClients) *http.Server {
+// newTranscoder exposes every RPC of the backends to routes without a
+// handler, using the compiled descriptors or, when enabled, the ones the
+// backends serve through gRPC reflection.
+func newTranscoder(cfg *config.Config, grpcClients types.GRPCClients) *transcoder.Transcoder {
+	services := map[string]struct {
+		name    string
+		timeout time.Duration
+	}{
+		constant.ServiceAuthentication: {authpb.AuthenticationService_ServiceDesc.ServiceName, cfg.GRPCAuthenticationClient.Timeout},
+		constant.ServiceUpload:         {uploadpb.UploadService_ServiceDesc.ServiceName, cfg.GRPCUploadClient.Timeout},
+		constant.ServiceVideoCatalog:   {videocatalogpb.VideoCatalogService_ServiceDesc.ServiceName, cfg.GRPCVideoCatalogClient.Timeout},
+	}
+
+	backends := map[string]transcoder.Backend{}
+	for service, conn := range grpcClients.Conns {
+		desc, ok := services[service]
+		if !ok || conn == nil {
+			continue
+		}
+
+		backend := transcoder.Backend{Conn: conn, Service: desc.name, Timeout: desc.timeout}
+
+		if cfg.Transcoder != nil && cfg.Transcoder.Reflection {
+			ctx, cancel := context.WithTimeout(context.Background(), desc.timeout)
+			files, err := transcoder.LoadFromReflection(ctx, conn, desc.name)
+			cancel()
+
+			if err != nil {
+				logger.Warn().Str("service", desc.name).Err(err).Msg("Server reflection failed, using compiled descriptors")
+			} else {
+				backend.Resolver = files
+			}
+		}
+
+		backends[service] = backend
+	}
+
+	var opts transcoder.Options
+	if cfg.Transcoder != nil {
+		opts.MaxBodyBytes = cfg.Transcoder.MaxBodyBytes
+	}
+
+	return transcoder.NewWithOptions(backends, opts)
+}
+
+// NewHealthChecker checks the gRPC clients, services that are not listed in
+// cfg.CriticalServices are optional.
+func NewHealthChecker(cfg *config.Health, grpcClients types.GRPCClients) *health.Checker {
+	checks := map[string]health.CheckFunc{
+		constant.ServiceAuthentication: grpcClients.AuthClient.Health,
+		constant.ServiceUpload:         grpcClients.UploadClient.Health,
+		constant.ServiceVideoCatalog:   grpcClients.VideoCatalogClient.Health,
+	}
+
+	for _, name := range cfg.CriticalServices {
+		if _, ok := checks[name]; !ok {
+			logger.Fatal().Str("service", name).Msg("Unknown critical service in HEALTH_CRITICAL_SERVICES")
+		}
+	}
+
+	deps := []health.Dependency{}
+	for _, name := range []string{constant.ServiceAuthentication, constant.ServiceUpload, constant.ServiceVideoCatalog} {
+		deps = append(deps, health.Dependency{
+			Name:     name,
+			Critical: slices.Contains(cfg.CriticalServices, name),
+			Check:    checks[name],
+		})
+	}
+
+	return health.NewChecker(deps...)
+}
+
+// revokedFunc returns the logout revocations client remembers, see
+// authentication.CachedAuthenticationClient, or nil when it doesn't.
+func revokedFunc(client any) middleware.RevokedFunc {
+	if r, ok := client.(interface{ Revoked(token string) bool }); ok {
+		return r.Revoked
+	}
+
+	return nil
+}
+
+// newRateLimitStore returns the store cfg selects, the memory store unless
+// the redis one is configured. Invalid settings are reported by
+// config.RateLimit.Validate.
+func newRateLimitStore(cfg *config.RateLimit) ratelimit.Store {
+	if cfg == nil || !cfg.Enabled || cfg.Store != constant.RateLimitStoreRedis {
+		return ratelimit.NewMemoryStore()
+	}
+
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid rate limit redis url")
+	}
+
+	// an unreachable server fails requests open, see RateLimitMiddleware
+	return ratelimit.NewRedisStore(ratelimit.GoRedisScripter{Client: redis.NewClient(opts)}, "ratelimit:")
+}
+
+func newResponseCache(cfg *config.ResponseCache) responsecache.Store {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	return responsecache.NewMemoryStore(cfg.Size)
+}
+
+// NewServer builds the gateway's HTTP server, health endpoints are answered by
+// healthChecker, usually a health.Prober.
+func NewServer(cfg *config.Config, grpcClients types.GRPCClients, healthChecker handler.HealthChecker) *http.Server {
+	var routes *route.Table
+	if cfg.HTTPServer.RoutesFile != "" {
+		var err error
+		if routes, err = route.Load(cfg.HTTPServer.RoutesFile); err != nil {
+			logger.Fatal().Err(err).Msg("Invalid route table")
+		}
+	}
+	if !i18n.Supported(cfg.HTTPServer.DefaultLocale) {
+		logger.Fatal().Str("locale", cfg.HTTPServer.DefaultLocale).Strs("supported", i18n.Locales()).Msg("Unsupported default locale")
+	}
+
 	router := NewRouter(RouterConfig{
-		Env:                 env,
+		Env:                 cfg.App.Env,
 		AuthHandler:         handler.NewAuthHandler(grpcClients.AuthClient),
-		HealthHandler:       handler.NewHealthHandler(grpcClients),
+		HealthHandler:       handler.NewHealthHandler(healthChecker),
 		UploadHandler:       handler.NewUploadHandler(grpcClients.UploadClient),
 		VideoCatalogHandler: handler.NewVideoCatalogHandler(grpcClients.VideoCatalogClient),
-		VerifyToken:         grpcClients.AuthClient.VerifyToken,
+		VerifyToken:         verifyTokenFunc(cfg.JWT, grpcClients.AuthClient.VerifyToken, revokedFunc(grpcClients.AuthClient)),
+		RateLimit:           cfg.RateLimit,
+		RateLimitStore:      newRateLimitStore(cfg.RateLimit),
+		Routes:              routes,
+		Transcoder:          newTranscoder(cfg, grpcClients),
+		ResponseCache:       newResponseCache(cfg.ResponseCache),
+		BackendReady:        grpcClients.Ready,
+		ProblemDetails:      cfg.HTTPServer.ProblemDetailsByDefault,
+		DefaultLocale:       cfg.HTTPServer.DefaultLocale,
+		OpenAPIDocs:         cfg.HTTPServer.OpenAPIDocs,
+		TrustedProxies:      cfg.HTTPServer.TrustedProxies,
 	})
 
-	address := fmt.Sprintf("%v:%d", cfg.Host, cfg.Port)
+	address := fmt.Sprintf("%v:%d", cfg.HTTPServer.Host, cfg.HTTPServer.Port)
 
-	return &http.Server{
+	server := &http.Server{
 		Addr:    address,
 		Handler: router,
 	}
+
+	if cfg.HTTPServer.TLS != nil && cfg.HTTPServer.TLS.Enabled {
+		tlsConfig, err := NewTLSConfig(cfg.HTTPServer.TLS)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Invalid HTTPS config")
+		}
+		server.TLSConfig = tlsConfig
+	}
+
+	return server
 }
 
-func Serve(server *http.Server, listen func() error) error {
-	logger.Info("Starting HTTP server on %s", server.Addr)
+// Serve listens for HTTPS when the server has a TLS config, HTTP/2 is
+// negotiated over TLS, and for plain HTTP otherwise.
+func Serve(server *http.Server) error {
+	listen := server.ListenAndServe
+	scheme := "http"
+	if server.TLSConfig != nil {
+		// the certificate comes from the TLS config
+		listen = func() error { return server.ListenAndServeTLS("", "") }
+		scheme = "https"
+	}
+
+	logger.Info().Str("addr", server.Addr).Str("scheme", scheme).Msg("Starting HTTP server")
 
 	if err := listen(); err != nil {
-		return fmt.Errorf("HTTP server failed to start %v", err)
+		return fmt.Errorf("HTTP server failed to start %w", err)
 	}
 	return nil
 }
@@ -6,13 +6,19 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
 	myhttp "github.com/sagarmaheshwary/microservices-api-gateway/internal/http"
 	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/ratelimit"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/responsecache"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/route"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type mockAuthHandler struct {
@@ -38,6 +44,14 @@ func (m *mockAuthHandler) Logout(c *gin.Context) {
 
 type mockHealthHandler struct{ mock.Mock }
 
+func (m *mockHealthHandler) Live(c *gin.Context) {
+	m.Called(c)
+	c.String(http.StatusOK, "live")
+}
+func (m *mockHealthHandler) Ready(c *gin.Context) {
+	m.Called(c)
+	c.String(http.StatusOK, "ready")
+}
 func (m *mockHealthHandler) CheckAll(c *gin.Context) {
 	m.Called(c)
 	c.String(http.StatusOK, "health")
@@ -106,6 +120,8 @@ func TestNewRouter_AllRoutes_WithTestifyMocks(t *testing.T) {
 		mockCalls    func()
 	}{
 		{"health", "GET", "/health", "", 200, "health", false, func() { healthMock.On("CheckAll", mock.Anything).Once() }},
+		{"liveness", "GET", "/health/live", "", 200, "live", false, func() { healthMock.On("Live", mock.Anything).Once() }},
+		{"readiness", "GET", "/health/ready", "", 200, "ready", false, func() { healthMock.On("Ready", mock.Anything).Once() }},
 		{"metrics", "GET", "/metrics", "", 200, "", false, func() {}},
 
 		{"register", "POST", "/auth/register", "", 200, "register", false, func() { authMock.On("Register", mock.Anything).Once() }},
@@ -146,3 +162,298 @@ func TestNewRouter_AllRoutes_WithTestifyMocks(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRouter_RateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authMock := new(mockAuthHandler)
+
+	router := myhttp.NewRouter(myhttp.RouterConfig{
+		Env:                 "test",
+		AuthHandler:         authMock,
+		HealthHandler:       new(mockHealthHandler),
+		UploadHandler:       new(mockUploadHandler),
+		VideoCatalogHandler: new(mockVideoCatalogHandler),
+		RateLimit: &config.RateLimit{
+			Enabled:   true,
+			Algorithm: "token_bucket",
+			Auth:      &config.RateLimitRule{Requests: 1, Window: time.Minute, KeyBy: constant.RateLimitKeyByIP},
+		},
+		RateLimitStore: ratelimit.NewMemoryStore(),
+	})
+
+	authMock.On("Login", mock.Anything).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/login", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/register", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	authMock.AssertExpectations(t)
+}
+
+func TestNewRouter_TrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		expectedStatus int
+	}{
+		{name: "forwarded header of untrusted peer is ignored", expectedStatus: http.StatusTooManyRequests},
+		{name: "forwarded header of trusted proxy is used", trustedProxies: []string{"192.0.2.0/24"}, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authMock := new(mockAuthHandler)
+			authMock.On("Login", mock.Anything)
+
+			router := myhttp.NewRouter(myhttp.RouterConfig{
+				Env:                 "test",
+				AuthHandler:         authMock,
+				HealthHandler:       new(mockHealthHandler),
+				UploadHandler:       new(mockUploadHandler),
+				VideoCatalogHandler: new(mockVideoCatalogHandler),
+				RateLimit: &config.RateLimit{
+					Enabled:   true,
+					Algorithm: "token_bucket",
+					Auth:      &config.RateLimitRule{Requests: 1, Window: time.Minute, KeyBy: constant.RateLimitKeyByIP},
+				},
+				RateLimitStore: ratelimit.NewMemoryStore(),
+				TrustedProxies: tt.trustedProxies,
+			})
+
+			var w *httptest.ResponseRecorder
+			for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+				req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+				req.RemoteAddr = "192.0.2.10:1234"
+				req.Header.Set("X-Forwarded-For", ip)
+
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestNewRouter_CustomRouteTable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	videoMock := new(mockVideoCatalogHandler)
+
+	routes, err := route.Parse([]byte(`
+routes:
+  - method: GET
+    path: /v2/videos
+    service: video_catalog
+    rpc: FindAll
+    timeout: 1s
+    cache:
+      ttl: 60s
+`))
+	require.NoError(t, err)
+
+	router := myhttp.NewRouter(myhttp.RouterConfig{
+		Env:                 "test",
+		HealthHandler:       new(mockHealthHandler),
+		VideoCatalogHandler: videoMock,
+		Routes:              routes,
+	})
+
+	videoMock.On("FindAll", mock.Anything).Run(func(args mock.Arguments) {
+		_, ok := args.Get(0).(*gin.Context).Request.Context().Deadline()
+		assert.True(t, ok, "route timeout should set a deadline")
+	}).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/videos", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/videos", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "routes missing from the table are not registered")
+
+	videoMock.AssertExpectations(t)
+}
+
+func TestNewRouter_ResponseCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uploadMock := new(mockUploadHandler)
+	videoMock := new(mockVideoCatalogHandler)
+
+	router := myhttp.NewRouter(myhttp.RouterConfig{
+		Env:                 "test",
+		AuthHandler:         new(mockAuthHandler),
+		HealthHandler:       new(mockHealthHandler),
+		UploadHandler:       uploadMock,
+		VideoCatalogHandler: videoMock,
+		VerifyToken: func(ctx context.Context, in *authpb.VerifyTokenRequest, token string) (*authpb.VerifyTokenResponse, error) {
+			return &authpb.VerifyTokenResponse{Data: &authpb.VerifyTokenResponseData{User: &authpb.User{Id: 1}}}, nil
+		},
+		ResponseCache: responsecache.NewMemoryStore(10),
+	})
+
+	videoMock.On("FindAll", mock.Anything).Twice()
+	uploadMock.On("UploadedWebhook", mock.Anything).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/videos", nil))
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, "public, max-age=30", w.Header().Get("Cache-Control"))
+
+	req := httptest.NewRequest(http.MethodGet, "/videos", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, "public, max-age=30", w.Header().Get("Cache-Control"))
+
+	req = httptest.NewRequest(http.MethodPost, "/videos/upload/webhook", nil)
+	req.Header.Set("Authorization", "Bearer faketoken")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/videos", nil))
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"), "webhook should invalidate the video list")
+
+	videoMock.AssertExpectations(t)
+	uploadMock.AssertExpectations(t)
+}
+
+func TestNewRouter_BackendReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authMock := new(mockAuthHandler)
+	healthMock := new(mockHealthHandler)
+
+	router := myhttp.NewRouter(myhttp.RouterConfig{
+		Env:                 "test",
+		AuthHandler:         authMock,
+		HealthHandler:       healthMock,
+		UploadHandler:       new(mockUploadHandler),
+		VideoCatalogHandler: new(mockVideoCatalogHandler),
+		BackendReady: map[string]func() bool{
+			constant.ServiceAuthentication: func() bool { return true },
+			constant.ServiceVideoCatalog:   func() bool { return false },
+		},
+	})
+
+	authMock.On("Login", mock.Anything).Once()
+	healthMock.On("Live", mock.Anything).Once()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "ready backend", method: http.MethodPost, path: "/auth/login", expectedStatus: http.StatusOK},
+		{name: "backend not ready", method: http.MethodGet, path: "/videos", expectedStatus: http.StatusServiceUnavailable},
+		{name: "gateway routes are not gated", method: http.MethodGet, path: "/health/live", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	authMock.AssertExpectations(t)
+	healthMock.AssertExpectations(t)
+}
+
+func TestNewRouter_ProblemDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := myhttp.NewRouter(myhttp.RouterConfig{
+		Env:                 "test",
+		AuthHandler:         new(mockAuthHandler),
+		HealthHandler:       new(mockHealthHandler),
+		UploadHandler:       new(mockUploadHandler),
+		VideoCatalogHandler: new(mockVideoCatalogHandler),
+		ProblemDetails:      true,
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "token verification abort", path: "/auth/profile", expectedStatus: http.StatusUnauthorized},
+		{name: "unknown route", path: "/missing", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), `"instance":"`+tt.path+`"`)
+		})
+	}
+}
+
+func TestNewRouter_Locale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		defaultLocale  string
+		acceptLanguage string
+		accept         string
+		expectedBody   string
+	}{
+		{
+			name:           "negotiated locale",
+			acceptLanguage: "es-AR,en;q=0.5",
+			expectedBody:   `{"message":"Recurso no encontrado","data":{}}`,
+		},
+		{
+			name:          "default locale",
+			defaultLocale: "fr",
+			expectedBody:  `{"message":"Ressource introuvable","data":{}}`,
+		},
+		{
+			name:           "problem document",
+			acceptLanguage: "es",
+			accept:         "application/problem+json",
+			expectedBody:   `{"type":"about:blank","title":"No encontrado","status":404,"detail":"Recurso no encontrado","instance":"/missing"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := myhttp.NewRouter(myhttp.RouterConfig{
+				Env:                 "test",
+				AuthHandler:         new(mockAuthHandler),
+				HealthHandler:       new(mockHealthHandler),
+				UploadHandler:       new(mockUploadHandler),
+				VideoCatalogHandler: new(mockVideoCatalogHandler),
+				Middlewares:         []gin.HandlerFunc{gin.Recovery()},
+				DefaultLocale:       tt.defaultLocale,
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
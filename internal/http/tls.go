@@ -0,0 +1,72 @@
+package http
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/tlsconfig"
+)
+
+// NewTLSConfig returns the config of the HTTPS listener, the certificate is
+// reloaded when its files change.
+func NewTLSConfig(cfg *config.HTTPServerTLS) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("HTTPS requires HTTP_TLS_CERT_FILE and HTTP_TLS_KEY_FILE")
+	}
+
+	minVersion, err := tlsconfig.ParseVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherSuites, err := tlsconfig.ParseCipherSuites(cfg.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := tlsconfig.NewSource(tlsconfig.Options{
+		CertFile:     cfg.CertFile,
+		KeyFile:      cfg.KeyFile,
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return source.ServerConfig(), nil
+}
+
+// NewRedirectServer listens for plain HTTP on cfg.TLS.RedirectPort and
+// redirects every request to HTTPS on cfg.Port.
+func NewRedirectServer(cfg *config.HTTPServer) *http.Server {
+	return &http.Server{
+		Addr:    fmt.Sprintf("%v:%d", cfg.Host, cfg.TLS.RedirectPort),
+		Handler: RedirectHandler(cfg.Port),
+	}
+}
+
+// RedirectHandler redirects to the same URL over HTTPS on port, 308 keeps the
+// method and body of the request.
+func RedirectHandler(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		}
+
+		url := *r.URL
+		url.Scheme = "https"
+		url.Host = host
+
+		http.Redirect(w, r, url.String(), http.StatusPermanentRedirect)
+	})
+}
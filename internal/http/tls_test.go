@@ -0,0 +1,170 @@
+package http_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	myhttp "github.com/sagarmaheshwary/microservices-api-gateway/internal/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert writes a certificate for 127.0.0.1 and returns its
+// files and a pool trusting it.
+func writeSelfSignedCert(t *testing.T) (string, string, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gateway"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return certFile, keyFile, pool
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	return lis.Addr().String()
+}
+
+func TestServe_TLS(t *testing.T) {
+	certFile, keyFile, pool := writeSelfSignedCert(t)
+
+	tlsConfig, err := myhttp.NewTLSConfig(&config.HTTPServerTLS{Enabled: true, CertFile: certFile, KeyFile: keyFile, MinVersion: "1.2"})
+	require.NoError(t, err)
+
+	server := &http.Server{
+		Addr:      freeAddr(t),
+		TLSConfig: tlsConfig,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, r.Proto)
+		}),
+	}
+	go myhttp.Serve(server)
+	t.Cleanup(func() { server.Close() })
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", server.Addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	tests := []struct {
+		name          string
+		maxVersion    uint16
+		expectedProto string
+		expectErr     bool
+	}{
+		{name: "negotiates HTTP/2", maxVersion: tls.VersionTLS13, expectedProto: "HTTP/2.0"},
+		{name: "rejects versions below the minimum", maxVersion: tls.VersionTLS11, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: &http.Transport{
+				TLSClientConfig:   &tls.Config{RootCAs: pool, MaxVersion: tt.maxVersion},
+				ForceAttemptHTTP2: true,
+			}}
+
+			res, err := client.Get("https://" + server.Addr)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedProto, res.Proto)
+		})
+	}
+}
+
+func TestNewTLSConfig_Invalid(t *testing.T) {
+	certFile, keyFile, _ := writeSelfSignedCert(t)
+
+	tests := []struct {
+		name string
+		cfg  *config.HTTPServerTLS
+	}{
+		{name: "missing key file", cfg: &config.HTTPServerTLS{CertFile: certFile}},
+		{name: "unknown min version", cfg: &config.HTTPServerTLS{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.4"}},
+		{name: "insecure cipher suite", cfg: &config.HTTPServerTLS{CertFile: certFile, KeyFile: keyFile, CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := myhttp.NewTLSConfig(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		port     int
+		target   string
+		expected string
+	}{
+		{name: "default HTTPS port", port: 443, target: "http://gateway.example.com/videos?page=2", expected: "https://gateway.example.com/videos?page=2"},
+		{name: "custom HTTPS port", port: 4443, target: "http://gateway.example.com:8080/auth/login", expected: "https://gateway.example.com:4443/auth/login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			myhttp.RedirectHandler(tt.port).ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.target, nil))
+
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, tt.expected, w.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language the gateway is written in, messages missing
+// from another catalog fall back to it.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var files embed.FS
+
+// catalog is a locales/<locale>.json file. Messages are keyed by their English
+// text, the constant.Message* strings, validation templates by rule.
+type catalog struct {
+	Messages   map[string]string `json:"messages"`
+	Validation map[string]string `json:"validation"`
+}
+
+var catalogs = mustLoad()
+
+func mustLoad() map[string]*catalog {
+	entries, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := map[string]*catalog{}
+	for _, entry := range entries {
+		b, err := files.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		var c catalog
+		if err := json.Unmarshal(b, &c); err != nil {
+			panic("invalid catalog " + entry.Name() + ": " + err.Error())
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = &c
+	}
+
+	return catalogs
+}
+
+// Locales returns the supported locales, sorted.
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// T translates a response message, messages without a translation are
+// returned as is, e.g. ones already localized by a backend.
+func T(locale, message string) string {
+	for _, l := range []string{locale, DefaultLocale} {
+		if c, ok := catalogs[l]; ok {
+			if translated, ok := c.Messages[message]; ok {
+				return translated
+			}
+		}
+	}
+
+	return message
+}
+
+// Validation returns the validation message template of a rule.
+func Validation(locale, key string) (string, bool) {
+	for _, l := range []string{locale, DefaultLocale} {
+		if c, ok := catalogs[l]; ok {
+			if template, ok := c.Validation[key]; ok {
+				return template, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Negotiate picks the supported locale preferred by an Accept-Language
+// header. A region falls back to its language, "es-MX" matches "es", and
+// fallback is used when nothing matches.
+func Negotiate(acceptLanguage, fallback string) string {
+	type preference struct {
+		tag string
+		q   float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		preferences = append(preferences, preference{tag: strings.ToLower(tag), q: q})
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].q > preferences[j].q })
+
+	for _, p := range preferences {
+		if p.tag == "*" {
+			return fallback
+		}
+		if Supported(p.tag) {
+			return p.tag
+		}
+		if base, _, ok := strings.Cut(p.tag, "-"); ok && Supported(base) {
+			return base
+		}
+	}
+
+	return fallback
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale of ctx, or DefaultLocale outside of a
+// request.
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok {
+		return locale
+	}
+
+	return DefaultLocale
+}
+
+// Negotiated reports whether ctx carries a locale picked for a request.
+func Negotiated(ctx context.Context) bool {
+	_, ok := ctx.Value(contextKey{}).(string)
+	return ok
+}
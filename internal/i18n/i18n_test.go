@@ -0,0 +1,112 @@
+package i18n_test
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "no header", acceptLanguage: "", expected: "en"},
+		{name: "exact match", acceptLanguage: "es", expected: "es"},
+		{name: "region falls back to its language", acceptLanguage: "fr-CA", expected: "fr"},
+		{name: "case insensitive", acceptLanguage: "ES-mx", expected: "es"},
+		{name: "highest quality wins", acceptLanguage: "es;q=0.5, fr;q=0.9, en;q=0.1", expected: "fr"},
+		{name: "unsupported languages are skipped", acceptLanguage: "de-DE, ja;q=0.8, es;q=0.3", expected: "es"},
+		{name: "zero quality is refused", acceptLanguage: "es;q=0, fr;q=0.1", expected: "fr"},
+		{name: "nothing supported", acceptLanguage: "de, ja", expected: "en"},
+		{name: "wildcard", acceptLanguage: "de, *;q=0.5", expected: "en"},
+		{name: "malformed quality is ignored", acceptLanguage: "es;q=abc, fr", expected: "fr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, i18n.Negotiate(tt.acceptLanguage, i18n.DefaultLocale))
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		locale   string
+		message  string
+		expected string
+	}{
+		{"en", constant.MessageBadRequest, "Bad Request"},
+		{"es", constant.MessageBadRequest, "Solicitud incorrecta"},
+		{"fr", constant.MessageAlive, "La passerelle est active !"},
+		{"de", constant.MessageBadRequest, "Bad Request"},
+		{"es", "Video no encontrado", "Video no encontrado"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, i18n.T(tt.locale, tt.message))
+	}
+}
+
+var placeholder = regexp.MustCompile(`\{\w+\}`)
+
+// TestCatalogs_Complete makes sure every locale translates every message and
+// validation rule of the default one, with the same placeholders.
+func TestCatalogs_Complete(t *testing.T) {
+	messages := []string{
+		constant.MessageOK, constant.MessageCreated, constant.MessageBadRequest,
+		constant.MessageUnauthorized, constant.MessageForbidden, constant.MessageNotFound,
+		constant.MessageConflict, constant.MessageTooManyRequests, constant.MessageClientClosedRequest,
+		constant.MessageInternalServerError, constant.MessageNotImplemented,
+		constant.MessageServiceUnavailable, constant.MessageGatewayTimeout,
+		constant.MessageServicesUnhealthy, constant.MessageServicesHealthy,
+		constant.MessageAlive, constant.MessageReady, constant.MessageNotReady,
+	}
+	rules := []string{
+		"required", "invalid", "email", "uuid", "min", "gte", "max", "lte",
+		"min.string", "gte.string", "max.string", "lte.string", "oneof", "alphanum",
+		"excluded_with", "name", "password.length", "password.lower",
+		"password.upper", "password.digit",
+	}
+
+	assert.Contains(t, i18n.Locales(), i18n.DefaultLocale)
+
+	for _, locale := range i18n.Locales() {
+		t.Run(locale, func(t *testing.T) {
+			for _, message := range messages {
+				translated := i18n.T(locale, message)
+				if locale != i18n.DefaultLocale {
+					assert.NotEqual(t, message, translated, "%q is not translated", message)
+				}
+			}
+
+			for _, rule := range rules {
+				template, _ := i18n.Validation(locale, rule)
+				expected, _ := i18n.Validation(i18n.DefaultLocale, rule)
+				if locale != i18n.DefaultLocale {
+					assert.NotEqual(t, expected, template, "%q is not translated", rule)
+				}
+
+				got, want := placeholder.FindAllString(template, -1), placeholder.FindAllString(expected, -1)
+				sort.Strings(got)
+				sort.Strings(want)
+				assert.Equal(t, want, got, "placeholders of %q", rule)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, i18n.DefaultLocale, i18n.FromContext(context.Background()))
+	assert.False(t, i18n.Negotiated(context.Background()))
+
+	ctx := i18n.NewContext(context.Background(), "es")
+	assert.Equal(t, "es", i18n.FromContext(ctx))
+	assert.True(t, i18n.Negotiated(ctx))
+}
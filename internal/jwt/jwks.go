@@ -0,0 +1,240 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/singleflight"
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// minRefreshInterval throttles refreshes triggered by unknown key ids so
+// tokens with made up ids cannot hammer the JWKS endpoint.
+const minRefreshInterval = 10 * time.Second
+
+// fetchTimeout bounds a refresh, it does not depend on the request that
+// triggered it.
+const fetchTimeout = 5 * time.Second
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// JWKS is a KeySet backed by a JSON Web Key Set endpoint. Keys are cached and
+// refetched once RefreshInterval has passed or when a token is signed by a
+// key id that is not cached yet, which picks up rotated keys.
+//
+// Only one refresh runs at a time. Lookups of cached keys don't wait for it,
+// lookups of unknown key ids share it.
+type JWKS struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+	now             func() time.Time
+	refreshes       *singleflight.Group[struct{}, struct{}]
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	refreshing  bool
+}
+
+func NewJWKS(url string, refreshInterval time.Duration, client *http.Client) *JWKS {
+	return NewJWKSWithClock(url, refreshInterval, client, time.Now)
+}
+
+func NewJWKSWithClock(url string, refreshInterval time.Duration, client *http.Client, now func() time.Time) *JWKS {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	return &JWKS{
+		url:             url,
+		client:          client,
+		refreshInterval: refreshInterval,
+		now:             now,
+		refreshes:       singleflight.NewGroup[struct{}, struct{}](),
+		keys:            map[string]crypto.PublicKey{},
+	}
+}
+
+func (j *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	now := j.now()
+
+	j.mu.Lock()
+	key, ok := j.keys[kid]
+	start := !j.refreshing && now.Sub(j.attemptedAt) > minRefreshInterval &&
+		(!ok || now.Sub(j.fetchedAt) > j.refreshInterval)
+	if start {
+		j.refreshing = true
+		j.attemptedAt = now
+	}
+	wait := !ok && j.refreshing
+	j.mu.Unlock()
+
+	if ok {
+		if start {
+			// the cached key keeps being served while the keys are refetched
+			go j.refresh(context.WithoutCancel(ctx), now)
+		}
+		return key, nil
+	}
+
+	if wait {
+		if err := j.refresh(ctx, now); err != nil {
+			return nil, err
+		}
+
+		j.mu.Lock()
+		key, ok = j.keys[kid]
+		j.mu.Unlock()
+		if ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+}
+
+// refresh joins the pending refresh, or runs it when nobody has yet, and
+// returns once it is done or ctx is. The fetch is not cancelled with ctx,
+// other lookups may be waiting for it. On failure the previous keys are kept.
+func (j *JWKS) refresh(ctx context.Context, now time.Time) error {
+	_, _, err := j.refreshes.Do(ctx, struct{}{}, func(ctx context.Context) (struct{}, error) {
+		j.mu.Lock()
+		pending := j.refreshing
+		j.mu.Unlock()
+		if !pending {
+			// joined after the refresh finished
+			return struct{}{}, nil
+		}
+
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+
+		keys, err := j.fetch(fetchCtx)
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+
+		j.refreshing = false
+		if err != nil {
+			logger.Ctx(ctx).Warn().Str("url", j.url).Err(err).Msg("Failed to fetch JWKS")
+			return struct{}{}, nil
+		}
+		j.keys = keys
+		j.fetchedAt = now
+
+		return struct{}{}, nil
+	})
+
+	return err
+}
+
+func (j *JWKS) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			logger.Warn().Str("kid", k.Kid).Err(err).Msg("Skipping JWKS key")
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
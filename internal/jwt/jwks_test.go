@@ -0,0 +1,202 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jwksServer struct {
+	mu       sync.Mutex
+	keys     map[string]*rsa.PublicKey
+	status   int
+	requests int
+	// block holds requests until it is closed
+	block chan struct{}
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	block := s.block
+	s.requests++
+	s.mu.Unlock()
+
+	if block != nil {
+		<-block
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+
+	keys := []map[string]string{}
+	for kid, key := range s.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+}
+
+func (s *jwksServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func (s *jwksServer) set(keys map[string]*rsa.PublicKey, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = keys
+	s.status = status
+}
+
+func TestJWKS_Rotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	handler := &jwksServer{keys: map[string]*rsa.PublicKey{"old": &oldKey.PublicKey}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	clock := now
+	jwks := jwt.NewJWKSWithClock(server.URL, time.Hour, server.Client(), func() time.Time { return clock })
+	ctx := context.Background()
+
+	key, err := jwks.Key(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, &oldKey.PublicKey, key)
+
+	_, err = jwks.Key(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, 1, handler.count(), "cached keys should be reused")
+
+	handler.set(map[string]*rsa.PublicKey{"old": &oldKey.PublicKey, "new": &newKey.PublicKey}, 0)
+
+	// unknown key ids only trigger a refetch once the throttle has passed
+	_, err = jwks.Key(ctx, "new")
+	assert.ErrorIs(t, err, jwt.ErrKeyNotFound)
+	assert.Equal(t, 1, handler.count())
+
+	clock = clock.Add(time.Minute)
+	key, err = jwks.Key(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, &newKey.PublicKey, key)
+	assert.Equal(t, 2, handler.count())
+}
+
+func TestJWKS_KeepsKeysWhenRefreshFails(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	handler := &jwksServer{keys: map[string]*rsa.PublicKey{"kid": &rsaKey.PublicKey}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	clock := now
+	jwks := jwt.NewJWKSWithClock(server.URL, time.Minute, server.Client(), func() time.Time { return clock })
+	ctx := context.Background()
+
+	_, err = jwks.Key(ctx, "kid")
+	require.NoError(t, err)
+
+	handler.set(nil, http.StatusInternalServerError)
+	clock = clock.Add(2 * time.Minute)
+
+	key, err := jwks.Key(ctx, "kid")
+	require.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+	assert.Eventually(t, func() bool { return handler.count() == 2 }, time.Second, time.Millisecond)
+
+	key, err = jwks.Key(ctx, "kid")
+	require.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+}
+
+func TestJWKS_SlowRefresh(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	handler := &jwksServer{keys: map[string]*rsa.PublicKey{"old": &oldKey.PublicKey}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	clock := now
+	jwks := jwt.NewJWKSWithClock(server.URL, time.Minute, server.Client(), func() time.Time { return clock })
+
+	_, err = jwks.Key(context.Background(), "old")
+	require.NoError(t, err)
+
+	block := make(chan struct{})
+	handler.mu.Lock()
+	handler.keys = map[string]*rsa.PublicKey{"old": &oldKey.PublicKey, "new": &newKey.PublicKey}
+	handler.block = block
+	handler.mu.Unlock()
+	clock = clock.Add(2 * time.Minute)
+
+	// cached keys are served while the stale keys are refetched
+	key, err := jwks.Key(context.Background(), "old")
+	require.NoError(t, err)
+	assert.Equal(t, &oldKey.PublicKey, key)
+	require.Eventually(t, func() bool { return handler.count() == 2 }, time.Second, time.Millisecond)
+
+	// a lookup giving up leaves the refresh running for the others
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = jwks.Key(ctx, "new")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done := make(chan error)
+	go func() {
+		_, err := jwks.Key(context.Background(), "new")
+		done <- err
+	}()
+
+	close(block)
+	require.NoError(t, <-done)
+	assert.Equal(t, 2, handler.count(), "lookups share the pending refresh")
+}
+
+func TestVerifier_WithJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(&jwksServer{keys: map[string]*rsa.PublicKey{"kid": &rsaKey.PublicKey}})
+	defer server.Close()
+
+	verifier := jwt.NewVerifier(jwt.Options{
+		KeySet: jwt.NewJWKS(server.URL, time.Hour, server.Client()),
+		Now:    func() time.Time { return now },
+	})
+
+	claims, err := verifier.Verify(context.Background(), signRS256(t, rsaKey, "kid", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "1", claims.Subject)
+}
@@ -0,0 +1,326 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for tokens that are malformed, badly signed
+	// or fail a claim check.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrUnverifiable is returned when the token cannot be checked locally,
+	// e.g. its signing key is unknown or the key set is unreachable.
+	ErrUnverifiable = errors.New("token cannot be verified locally")
+)
+
+// KeySet resolves the public key a token was signed with by its "kid" header.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	// Raw holds every claim of the payload, numbers are json.Number.
+	Raw map[string]any
+}
+
+func (c *Claims) String(name string) string {
+	s, _ := c.Raw[name].(string)
+	return s
+}
+
+type Options struct {
+	// Secret verifies HS256/HS384/HS512 tokens.
+	Secret []byte
+	// KeySet verifies RS256/RS384/RS512 and ES256/ES384/ES512 tokens.
+	KeySet   KeySet
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+	Now      func() time.Time
+}
+
+type Verifier struct {
+	opts Options
+}
+
+func NewVerifier(opts Options) *Verifier {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return &Verifier{opts: opts}
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	if err := v.verifySignature(ctx, h, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	raw := map[string]any{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+
+	claims, err := parseClaims(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) verifySignature(ctx context.Context, h header, signed []byte, signature []byte) error {
+	newHash, err := hashFor(h.Alg)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(h.Alg, "HS") {
+		if len(v.opts.Secret) == 0 {
+			return fmt.Errorf("%w: no secret configured for %s", ErrUnverifiable, h.Alg)
+		}
+
+		mac := hmac.New(newHash, v.opts.Secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+	}
+
+	if v.opts.KeySet == nil {
+		return fmt.Errorf("%w: no key set configured for %s", ErrUnverifiable, h.Alg)
+	}
+
+	key, err := v.opts.KeySet.Key(ctx, h.Kid)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnverifiable, err)
+	}
+
+	hasher := newHash()
+	hasher.Write(signed)
+	digest := hasher.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(h.Alg, "RS") {
+			return fmt.Errorf("%w: %s does not match an RSA key", ErrInvalidToken, h.Alg)
+		}
+		if err := rsa.VerifyPKCS1v15(pub, cryptoHash(h.Alg), digest, signature); err != nil {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(h.Alg, "ES") || len(signature) != 2*size {
+			return fmt.Errorf("%w: %s does not match an EC key", ErrInvalidToken, h.Alg)
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported key type %T", ErrUnverifiable, key)
+	}
+
+	return nil
+}
+
+func (v *Verifier) validateClaims(c *Claims) error {
+	now := v.opts.Now()
+
+	if c.ExpiresAt.IsZero() || !now.Before(c.ExpiresAt.Add(v.opts.Leeway)) {
+		return fmt.Errorf("%w: token is expired", ErrInvalidToken)
+	}
+
+	if !c.NotBefore.IsZero() && now.Add(v.opts.Leeway).Before(c.NotBefore) {
+		return fmt.Errorf("%w: token is not valid yet", ErrInvalidToken)
+	}
+
+	if v.opts.Issuer != "" && c.Issuer != v.opts.Issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, c.Issuer)
+	}
+
+	if v.opts.Audience != "" && !slices.Contains(c.Audience, v.opts.Audience) {
+		return fmt.Errorf("%w: token is not intended for %q", ErrInvalidToken, v.opts.Audience)
+	}
+
+	return nil
+}
+
+func parseClaims(raw map[string]any) (*Claims, error) {
+	c := &Claims{Raw: raw}
+	c.Subject = stringOrNumber(raw["sub"])
+	c.Issuer, _ = raw["iss"].(string)
+
+	switch aud := raw["aud"].(type) {
+	case string:
+		c.Audience = []string{aud}
+	case []any:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				c.Audience = append(c.Audience, s)
+			}
+		}
+	}
+
+	var err error
+	if c.ExpiresAt, err = numericDate(raw, "exp"); err != nil {
+		return nil, err
+	}
+	if c.NotBefore, err = numericDate(raw, "nbf"); err != nil {
+		return nil, err
+	}
+	if c.IssuedAt, err = numericDate(raw, "iat"); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// maxNumericDate is the last second of year 9999, later dates are rejected
+// rather than overflowing time.Time arithmetic.
+const maxNumericDate = 253402300799
+
+func numericDate(raw map[string]any, name string) (time.Time, error) {
+	v, ok := raw[name]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s is not a numeric date", ErrInvalidToken, name)
+	}
+
+	f, err := n.Float64()
+	if err != nil || math.IsNaN(f) || f < 0 || f > maxNumericDate {
+		return time.Time{}, fmt.Errorf("%w: %s is not a numeric date", ErrInvalidToken, name)
+	}
+
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))), nil
+}
+
+func stringOrNumber(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case json.Number:
+		return s.String()
+	}
+	return ""
+}
+
+// ParseUnverified decodes the claims of token, which may carry a "Bearer "
+// prefix, without checking its signature or claims. Only use them where a
+// forged value does no harm, e.g. to bound how long the token is remembered.
+func ParseUnverified(token string) (*Claims, error) {
+	parts := strings.Split(TrimBearer(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	raw := map[string]any{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+
+	return parseClaims(raw)
+}
+
+// TrimBearer returns the token of an Authorization header value.
+func TrimBearer(header string) string {
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+
+	return header
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
+
+func hashFor(alg string) (func() hash.Hash, error) {
+	if len(alg) != 5 || !slices.Contains([]string{"HS", "RS", "ES"}, alg[:2]) {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+	}
+
+	switch alg[2:] {
+	case "256":
+		return sha256.New, nil
+	case "384":
+		return sha512.New384, nil
+	case "512":
+		return sha512.New, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+}
+
+func cryptoHash(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
@@ -0,0 +1,266 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type staticKeySet map[string]crypto.PublicKey
+
+func (s staticKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := s[kid]; ok {
+		return key, nil
+	}
+	return nil, jwt.ErrKeyNotFound
+}
+
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret []byte, claims map[string]any) string {
+	t.Helper()
+
+	signed := encodeSegment(t, map[string]any{"alg": "HS256", "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+
+	signed := encodeSegment(t, map[string]any{"alg": "RS256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+
+	signed := encodeSegment(t, map[string]any{"alg": "ES256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":   "1",
+		"name":  "John Doe",
+		"email": "john@example.com",
+		"iss":   "authentication-service",
+		"aud":   []string{"api-gateway"},
+		"iat":   now.Add(-time.Minute).Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifier_HS256(t *testing.T) {
+	secret := []byte("secret")
+	verifier := jwt.NewVerifier(jwt.Options{
+		Secret:   secret,
+		Issuer:   "authentication-service",
+		Audience: "api-gateway",
+		Now:      func() time.Time { return now },
+	})
+
+	claims, err := verifier.Verify(context.Background(), signHS256(t, secret, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "1", claims.Subject)
+	assert.Equal(t, "John Doe", claims.String("name"))
+	assert.Equal(t, []string{"api-gateway"}, claims.Audience)
+	assert.True(t, now.Add(time.Hour).Equal(claims.ExpiresAt))
+}
+
+func TestVerifier_Asymmetric(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	verifier := jwt.NewVerifier(jwt.Options{
+		KeySet: staticKeySet{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey},
+		Now:    func() time.Time { return now },
+	})
+
+	tests := []struct {
+		name      string
+		token     string
+		expectErr error
+	}{
+		{
+			name:  "RS256",
+			token: signRS256(t, rsaKey, "rsa", validClaims()),
+		},
+		{
+			name:  "ES256",
+			token: signES256(t, ecKey, "ec", validClaims()),
+		},
+		{
+			name:      "RS256 signed with the kid of an EC key",
+			token:     signRS256(t, rsaKey, "ec", validClaims()),
+			expectErr: jwt.ErrInvalidToken,
+		},
+		{
+			name:      "unknown kid",
+			token:     signRS256(t, rsaKey, "missing", validClaims()),
+			expectErr: jwt.ErrUnverifiable,
+		},
+		{
+			name:      "HS256 without a secret",
+			token:     signHS256(t, []byte("secret"), validClaims()),
+			expectErr: jwt.ErrUnverifiable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "1", claims.Subject)
+		})
+	}
+}
+
+func TestVerifier_InvalidTokens(t *testing.T) {
+	secret := []byte("secret")
+
+	with := func(mutate func(map[string]any)) map[string]any {
+		claims := validClaims()
+		mutate(claims)
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "malformed",
+			token: "not-a-token",
+		},
+		{
+			name:  "wrong secret",
+			token: signHS256(t, []byte("other"), validClaims()),
+		},
+		{
+			name:  "unsigned",
+			token: encodeSegment(t, map[string]any{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + ".",
+		},
+		{
+			name:  "expired",
+			token: signHS256(t, secret, with(func(c map[string]any) { c["exp"] = now.Add(-time.Minute).Unix() })),
+		},
+		{
+			name:  "missing exp",
+			token: signHS256(t, secret, with(func(c map[string]any) { delete(c, "exp") })),
+		},
+		{
+			name:  "not yet valid",
+			token: signHS256(t, secret, with(func(c map[string]any) { c["nbf"] = now.Add(time.Minute).Unix() })),
+		},
+		{
+			name:  "exp beyond year 9999",
+			token: signHS256(t, secret, with(func(c map[string]any) { c["exp"] = json.Number("1e19") })),
+		},
+		{
+			name:  "exp out of float range",
+			token: signHS256(t, secret, with(func(c map[string]any) { c["exp"] = json.Number("1e400") })),
+		},
+		{
+			name:  "negative iat",
+			token: signHS256(t, secret, with(func(c map[string]any) { c["iat"] = -1 })),
+		},
+		{
+			name:  "wrong issuer",
+			token: signHS256(t, secret, with(func(c map[string]any) { c["iss"] = "someone-else" })),
+		},
+		{
+			name:  "wrong audience",
+			token: signHS256(t, secret, with(func(c map[string]any) { c["aud"] = "someone-else" })),
+		},
+	}
+
+	verifier := jwt.NewVerifier(jwt.Options{
+		Secret:   secret,
+		Issuer:   "authentication-service",
+		Audience: "api-gateway",
+		Now:      func() time.Time { return now },
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), tt.token)
+			assert.True(t, errors.Is(err, jwt.ErrInvalidToken), "expected ErrInvalidToken, got %v", err)
+		})
+	}
+}
+
+func TestVerifier_NumericDates(t *testing.T) {
+	secret := []byte("secret")
+	claims := validClaims()
+	claims["exp"] = json.Number("253402300798.5")
+
+	verifier := jwt.NewVerifier(jwt.Options{
+		Secret: secret,
+		Now:    func() time.Time { return now },
+	})
+
+	c, err := verifier.Verify(context.Background(), signHS256(t, secret, claims))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(9999, 12, 31, 23, 59, 58, 500_000_000, time.UTC), c.ExpiresAt.UTC())
+}
+
+func TestVerifier_Leeway(t *testing.T) {
+	secret := []byte("secret")
+	claims := validClaims()
+	claims["exp"] = now.Add(-10 * time.Second).Unix()
+
+	verifier := jwt.NewVerifier(jwt.Options{
+		Secret: secret,
+		Leeway: 30 * time.Second,
+		Now:    func() time.Time { return now },
+	})
+
+	_, err := verifier.Verify(context.Background(), signHS256(t, secret, claims))
+	assert.NoError(t, err)
+}
@@ -1,40 +1,165 @@
 package logger
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func Init() {
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
+type Options struct {
+	// Format is FormatConsole for humans or FormatJSON for log shippers.
+	Format string
+	// Level is a zerolog level name, e.g. "debug" or "warn".
+	Level  string
+	Output io.Writer
+	// Sampling applies to lines logged through Sampled, nil logs every line.
+	Sampling *Sampling
+}
+
+// Sampling lets Burst info and debug lines through per Period, after that only
+// every Rate-th line is written until the period is over. Warnings and errors
+// are never sampled.
+type Sampling struct {
+	Burst  uint32
+	Period time.Duration
+	Rate   uint32
+}
+
+var sampler zerolog.Sampler
+
+type fieldsKey struct{}
+
+// Init configures the global logger, it is safe to call again once the config
+// has been loaded.
+func Init(opts Options) error {
+	level := zerolog.InfoLevel
+	if opts.Level != "" {
+		var err error
+		if level, err = zerolog.ParseLevel(opts.Level); err != nil {
+			return fmt.Errorf("invalid log level %q", opts.Level)
+		}
+	}
+
+	out := opts.Output
+	if out == nil {
+		out = os.Stderr
+	}
+
+	switch opts.Format {
+	case "", FormatConsole:
+		out = consoleWriter(out)
+	case FormatJSON:
+		zerolog.TimeFieldFormat = time.RFC3339Nano
+	default:
+		return fmt.Errorf("invalid log format %q", opts.Format)
+	}
+
+	sampler = nil
+	if opts.Sampling != nil {
+		next := &zerolog.BasicSampler{N: opts.Sampling.Rate}
+		burst := &zerolog.BurstSampler{Burst: opts.Sampling.Burst, Period: opts.Sampling.Period, NextSampler: next}
+		sampler = zerolog.LevelSampler{DebugSampler: burst, InfoSampler: burst}
+	}
+
+	log.Logger = zerolog.New(out).Level(level).With().Timestamp().Logger()
+
+	return nil
+}
+
+// InitConsole configures the global logger with the defaults of Init, info
+// lines and above written to stderr for humans. Unlike Init it cannot fail, it
+// is used until the config is loaded.
+func InitConsole() {
+	sampler = nil
+	log.Logger = zerolog.New(consoleWriter(os.Stderr)).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+}
+
+func consoleWriter(out io.Writer) io.Writer {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = log.Output(zerolog.ConsoleWriter{
-		Out:        os.Stderr,
-		TimeFormat: "02/01/2006, 3:04:05 PM",
-	})
+	return zerolog.ConsoleWriter{Out: out, TimeFormat: "02/01/2006, 3:04:05 PM"}
+}
+
+func Debug() *zerolog.Event {
+	return log.Debug()
+}
+
+func Info() *zerolog.Event {
+	return log.Info()
+}
+
+func Warn() *zerolog.Event {
+	return log.Warn()
 }
 
-func Info(format string, v ...interface{}) {
-	log.Info().Msgf(format, v...)
+func Error() *zerolog.Event {
+	return log.Error()
 }
 
-func Warn(format string, v ...interface{}) {
-	log.Warn().Msgf(format, v...)
+func Fatal() *zerolog.Event {
+	return log.Fatal()
 }
 
-func Debug(format string, v ...interface{}) {
-	log.Debug().Msgf(format, v...)
+func Panic() *zerolog.Event {
+	return log.Panic()
 }
 
-func Error(format string, v ...interface{}) {
-	log.Error().Msgf(format, v...)
+// Ctx returns the global logger with the request scoped fields of ctx: the
+// request id, the OpenTelemetry trace id and the fields added by WithFields.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	l := log.Logger.With()
+
+	if id := requestid.FromContext(ctx); id != "" {
+		l = l.Str("request_id", id)
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		l = l.Str("trace_id", sc.TraceID().String())
+	}
+
+	if fields, ok := ctx.Value(fieldsKey{}).(map[string]any); ok {
+		l = l.Fields(fields)
+	}
+
+	logger := l.Logger()
+	return &logger
 }
 
-func Fatal(format string, v ...interface{}) {
-	log.Fatal().Msgf(format, v...)
+// Sampled is Ctx for high volume lines, e.g. one per request, they are
+// dropped according to Options.Sampling.
+func Sampled(ctx context.Context) *zerolog.Logger {
+	l := Ctx(ctx)
+	if sampler == nil {
+		return l
+	}
+
+	sampled := l.Sample(sampler)
+	return &sampled
 }
 
-func Panic(format string, v ...interface{}) {
-	log.Panic().Msgf(format, v...)
+// WithFields returns a copy of ctx whose logger, see Ctx, adds fields to every
+// line.
+func WithFields(ctx context.Context, fields map[string]any) context.Context {
+	merged := map[string]any{}
+	if parent, ok := ctx.Value(fieldsKey{}).(map[string]any); ok {
+		for k, v := range parent {
+			merged[k] = v
+		}
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
 }
@@ -0,0 +1,146 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func initJSON(t *testing.T, opts logger.Options) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	opts.Format = logger.FormatJSON
+	opts.Output = &buf
+	require.NoError(t, logger.Init(opts))
+	t.Cleanup(logger.InitConsole)
+
+	return &buf
+}
+
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	out := []map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		m := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		out = append(out, m)
+	}
+
+	return out
+}
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      logger.Options
+		expectErr bool
+	}{
+		{name: "defaults", opts: logger.Options{}},
+		{name: "json", opts: logger.Options{Format: logger.FormatJSON, Level: "debug"}},
+		{name: "invalid level", opts: logger.Options{Level: "loud"}, expectErr: true},
+		{name: "invalid format", opts: logger.Options{Format: "xml"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Output = &bytes.Buffer{}
+			err := logger.Init(tt.opts)
+			t.Cleanup(logger.InitConsole)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInit_Level(t *testing.T) {
+	buf := initJSON(t, logger.Options{Level: "warn"})
+
+	logger.Info().Msg("dropped")
+	logger.Warn().Str("service", "upload").Msg("kept")
+
+	got := lines(t, buf)
+	require.Len(t, got, 1)
+	assert.Equal(t, "kept", got[0]["message"])
+	assert.Equal(t, "upload", got[0]["service"])
+}
+
+func TestInitConsole(t *testing.T) {
+	initJSON(t, logger.Options{Level: "debug"})
+
+	logger.InitConsole()
+
+	assert.False(t, logger.Debug().Enabled())
+	assert.True(t, logger.Info().Enabled())
+}
+
+func TestCtx(t *testing.T) {
+	buf := initJSON(t, logger.Options{})
+
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	}))
+	ctx = requestid.NewContext(ctx, "req-1")
+	ctx = logger.WithFields(ctx, map[string]any{"user_id": 1})
+	ctx = logger.WithFields(ctx, map[string]any{"route": "/videos"})
+
+	logger.Ctx(ctx).Info().Msg("request scoped")
+	logger.Ctx(context.Background()).Info().Msg("background")
+
+	got := lines(t, buf)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "req-1", got[0]["request_id"])
+	assert.Equal(t, traceID.String(), got[0]["trace_id"])
+	assert.EqualValues(t, 1, got[0]["user_id"])
+	assert.Equal(t, "/videos", got[0]["route"])
+
+	assert.NotContains(t, got[1], "request_id")
+	assert.NotContains(t, got[1], "trace_id")
+}
+
+func TestSampled(t *testing.T) {
+	buf := initJSON(t, logger.Options{
+		Sampling: &logger.Sampling{Burst: 2, Period: time.Hour, Rate: 5},
+	})
+
+	for range 12 {
+		logger.Sampled(context.Background()).Info().Msg("request")
+	}
+	for range 3 {
+		logger.Sampled(context.Background()).Error().Msg("failed")
+	}
+
+	info, errors := 0, 0
+	for _, line := range lines(t, buf) {
+		switch line["level"] {
+		case "info":
+			info++
+		case "error":
+			errors++
+		}
+	}
+
+	// 2 lines of the burst, then every 5th of the remaining 10
+	assert.Equal(t, 4, info)
+	assert.Equal(t, 3, errors, "errors are never sampled")
+}
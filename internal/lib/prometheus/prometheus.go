@@ -30,10 +30,123 @@ var (
 		[]string{"type", "route"},
 	)
 
-	ServiceHealth = prometheuslib.NewGauge(prometheuslib.GaugeOpts{
-		Name: "service_health_status",
-		Help: "Health status of the service: 1=Healthy, 0=Unhealthy",
+	ServiceHealth = prometheuslib.NewGaugeVec(
+		prometheuslib.GaugeOpts{
+			Name: "service_health_status",
+			Help: "Health status of a downstream service: 1=Healthy, 0=Unhealthy",
+		},
+		[]string{"service"},
+	)
+
+	ServiceHealthTransitions = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "service_health_transitions_total",
+			Help: "Total number of times a downstream service went up or down.",
+		},
+		[]string{"service", "status"},
+	)
+
+	GatewayReady = prometheuslib.NewGauge(prometheuslib.GaugeOpts{
+		Name: "gateway_ready",
+		Help: "Readiness of the gateway: 1=Ready, 0=Not ready",
 	})
+
+	CircuitBreakerState = prometheuslib.NewGaugeVec(
+		prometheuslib.GaugeOpts{
+			Name: "grpc_client_circuit_breaker_state",
+			Help: "Circuit breaker state of a downstream gRPC service: 0=Closed, 1=Open, 2=Half-Open",
+		},
+		[]string{"service"},
+	)
+
+	GRPCClientRequests = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "grpc_client_requests_total",
+			Help: "Total number of gRPC calls made to downstream services by gRPC status code.",
+		},
+		[]string{"service", "method", "code"},
+	)
+
+	GRPCClientRequestDuration = prometheuslib.NewHistogramVec(
+		prometheuslib.HistogramOpts{
+			Name:    "grpc_client_request_duration_seconds",
+			Help:    "Histogram of gRPC call durations in seconds, including retries.",
+			Buckets: prometheuslib.DefBuckets,
+		},
+		[]string{"service", "method"},
+	)
+
+	GRPCClientInFlightRequests = prometheuslib.NewGaugeVec(
+		prometheuslib.GaugeOpts{
+			Name: "grpc_client_in_flight_requests",
+			Help: "Number of gRPC calls to downstream services currently in flight.",
+		},
+		[]string{"service", "method"},
+	)
+
+	GRPCClientConnectionState = prometheuslib.NewGaugeVec(
+		prometheuslib.GaugeOpts{
+			Name: "grpc_client_connection_state",
+			Help: "Connectivity state of the gRPC connection to a downstream service, 1 for the current state and 0 for the others.",
+		},
+		[]string{"service", "state"},
+	)
+
+	GRPCClientConnectionStateChanges = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "grpc_client_connection_state_changes_total",
+			Help: "Total number of connectivity state changes of the gRPC connection to a downstream service by new state.",
+		},
+		[]string{"service", "state"},
+	)
+
+	GRPCClientRetries = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "grpc_client_retries_total",
+			Help: "Total number of gRPC calls retried after a retryable failure.",
+		},
+		[]string{"service", "method", "code"},
+	)
+
+	GRPCClientRetriesThrottled = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "grpc_client_retries_throttled_total",
+			Help: "Total number of gRPC retries and hedged attempts not sent because the retry budget was exhausted.",
+		},
+		[]string{"service", "method"},
+	)
+
+	GRPCClientHedgedRequests = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "grpc_client_hedged_requests_total",
+			Help: "Total number of hedged gRPC attempts sent while an earlier attempt was still in flight.",
+		},
+		[]string{"service", "method"},
+	)
+
+	GRPCClientCoalescedRequests = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "grpc_client_coalesced_requests_total",
+			Help: "Total number of gRPC calls deduplicated by sharing the result of an identical in-flight call.",
+		},
+		[]string{"service", "method"},
+	)
+
+	TokenCacheRequests = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "token_cache_requests_total",
+			Help: "Total number of token verification cache lookups by result: hit or miss.",
+		},
+		[]string{"result"},
+	)
+
+	ResponseCacheRequests = prometheuslib.NewCounterVec(
+		prometheuslib.CounterOpts{
+			Name: "response_cache_requests_total",
+			Help: "Total number of response cache lookups by route and result: hit, miss or bypass.",
+		},
+		[]string{"route", "result"},
+	)
 )
 
 func RegisterMetrics() {
@@ -42,5 +155,19 @@ func RegisterMetrics() {
 		ErrorCount,
 		RequestDuration,
 		ServiceHealth,
+		ServiceHealthTransitions,
+		GatewayReady,
+		CircuitBreakerState,
+		GRPCClientRequests,
+		GRPCClientRequestDuration,
+		GRPCClientInFlightRequests,
+		GRPCClientConnectionState,
+		GRPCClientConnectionStateChanges,
+		GRPCClientRetries,
+		GRPCClientRetriesThrottled,
+		GRPCClientHedgedRequests,
+		GRPCClientCoalescedRequests,
+		TokenCacheRequests,
+		ResponseCacheRequests,
 	)
 }
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+)
+
+// BackendReadyMiddleware answers 503 until the backend serving the route has
+// been reached, so one backend still starting doesn't fail the other routes.
+func BackendReadyMiddleware(ready func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ready() {
+			c.Header("Retry-After", "5")
+			helper.RespondWithError(c, http.StatusServiceUnavailable, helper.PrepareResponse(constant.MessageServiceUnavailable, gin.H{}))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackendReadyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		ready          bool
+		expectedStatus int
+	}{
+		{name: "ready backend is served", ready: true, expectedStatus: http.StatusOK},
+		{name: "backend not ready yet", ready: false, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", middleware.BackendReadyMiddleware(func() bool { return tt.ready }), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.ready {
+				assert.Equal(t, "5", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControlMiddleware marks successful and not modified responses as
+// cacheable for ttl.
+// Responses of authenticated routes are only cacheable by the client.
+func CacheControlMiddleware(ttl time.Duration, private bool) gin.HandlerFunc {
+	visibility := "public"
+	if private {
+		visibility = "private"
+	}
+	value := fmt.Sprintf("%s, max-age=%d", visibility, int(ttl.Seconds()))
+
+	return func(c *gin.Context) {
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, value: value}
+		c.Next()
+	}
+}
+
+// cacheControlWriter sets Cache-Control right before the status is written,
+// once it is known whether the response succeeded.
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	value string
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	w.setHeader(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.setHeader(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.setHeader(w.Status())
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.setHeader(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *cacheControlWriter) setHeader(code int) {
+	if w.Written() || w.Header().Get("Cache-Control") != "" {
+		return
+	}
+
+	if (code >= http.StatusOK && code < http.StatusMultipleChoices) || code == http.StatusNotModified {
+		w.Header().Set("Cache-Control", w.value)
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+}
@@ -0,0 +1,56 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControlMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		private      bool
+		status       int
+		expectHeader string
+	}{
+		{
+			name:         "public success",
+			status:       http.StatusOK,
+			expectHeader: "public, max-age=30",
+		},
+		{
+			name:         "private success",
+			private:      true,
+			status:       http.StatusOK,
+			expectHeader: "private, max-age=30",
+		},
+		{
+			name:         "error response",
+			status:       http.StatusNotFound,
+			expectHeader: "no-store",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.CacheControlMiddleware(30*time.Second, tt.private))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(tt.status, gin.H{})
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.expectHeader, w.Header().Get("Cache-Control"))
+		})
+	}
+}
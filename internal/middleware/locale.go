@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+)
+
+// LocaleMiddleware negotiates the locale of the response from the
+// Accept-Language header, falling back to fallback, and attaches it to the
+// request context for the response helpers and the gRPC clients.
+func LocaleMiddleware(fallback string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Negotiate(c.GetHeader(constant.HeaderAcceptLanguage), fallback)
+
+		c.Request = c.Request.WithContext(i18n.NewContext(c.Request.Context(), locale))
+		c.Header(constant.HeaderContentLanguage, locale)
+		c.Writer.Header().Add("Vary", constant.HeaderAcceptLanguage)
+
+		c.Next()
+	}
+}
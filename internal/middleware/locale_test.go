@@ -0,0 +1,69 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	tests := []struct {
+		name            string
+		acceptLanguage  string
+		fallback        string
+		expectedLocale  string
+		expectedMessage string
+	}{
+		{
+			name:            "negotiates the locale",
+			acceptLanguage:  "es-ES,es;q=0.9,en;q=0.8",
+			fallback:        "en",
+			expectedLocale:  "es",
+			expectedMessage: "Solicitud incorrecta",
+		},
+		{
+			name:            "falls back to the default locale",
+			acceptLanguage:  "de",
+			fallback:        "fr",
+			expectedLocale:  "fr",
+			expectedMessage: "Requête invalide",
+		},
+		{
+			name:            "no header",
+			fallback:        "en",
+			expectedLocale:  "en",
+			expectedMessage: constant.MessageBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(middleware.LocaleMiddleware(tt.fallback))
+
+			var fromContext string
+			r.GET("/test", func(c *gin.Context) {
+				fromContext = i18n.FromContext(c.Request.Context())
+				helper.RespondWithError(c, http.StatusBadRequest, helper.PrepareResponse(constant.MessageBadRequest, gin.H{}))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(constant.HeaderAcceptLanguage, tt.acceptLanguage)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedLocale, fromContext)
+			assert.Equal(t, tt.expectedLocale, w.Header().Get(constant.HeaderContentLanguage))
+			assert.Equal(t, constant.HeaderAcceptLanguage, w.Header().Get("Vary"))
+			assert.JSONEq(t, `{"message":"`+tt.expectedMessage+`","data":{}}`, w.Body.String())
+		})
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/rs/zerolog/log"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 )
 
 func ZerologMiddleware() gin.HandlerFunc {
@@ -20,9 +20,10 @@ func ZerologMiddleware() gin.HandlerFunc {
 		method := c.Request.Method
 		clientIP := c.ClientIP()
 
-		event := log.Info()
+		// successful requests are the bulk of the log, only they are sampled
+		event := logger.Sampled(c.Request.Context()).Info()
 		if status >= 400 {
-			event = log.Error()
+			event = logger.Ctx(c.Request.Context()).Error()
 		}
 
 		event.
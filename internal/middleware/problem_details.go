@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+)
+
+// ProblemDetailsMiddleware picks the format of error responses, see
+// helper.RespondWithError. Clients get problem+json when they accept it, and
+// by default unless they ask for plain application/json.
+func ProblemDetailsMiddleware(byDefault bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(constant.ProblemDetails, wantsProblemDetails(c.GetHeader("Accept"), byDefault))
+		c.Next()
+	}
+}
+
+func wantsProblemDetails(accept string, byDefault bool) bool {
+	acceptsJSON := false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case helper.ContentTypeProblemJSON:
+			return true
+		case "application/json":
+			acceptsJSON = true
+		}
+	}
+
+	return byDefault && !acceptsJSON
+}
@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemDetailsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name                string
+		byDefault           bool
+		accept              string
+		expectedContentType string
+	}{
+		{name: "envelope by default", accept: "", expectedContentType: "application/json; charset=utf-8"},
+		{name: "client asks for problem+json", accept: "application/problem+json", expectedContentType: helper.ContentTypeProblemJSON},
+		{name: "problem+json among other types", accept: "text/html;q=0.9, application/problem+json;q=0.8", expectedContentType: helper.ContentTypeProblemJSON},
+		{name: "problem+json by default", byDefault: true, accept: "*/*", expectedContentType: helper.ContentTypeProblemJSON},
+		{name: "client asks for plain json", byDefault: true, accept: "application/json", expectedContentType: "application/json; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.ProblemDetailsMiddleware(tt.byDefault))
+			r.GET("/", func(c *gin.Context) {
+				helper.RespondWithError(c, http.StatusBadRequest, helper.PrepareResponse(constant.MessageBadRequest, gin.H{}))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestProblemDetailsMiddleware_Document(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.RequestIDMiddleware(), middleware.ProblemDetailsMiddleware(true))
+	r.POST("/auth/register", func(c *gin.Context) {
+		res := helper.PrepareResponse(constant.MessageBadRequest, gin.H{
+			"errors": map[string][]string{"email": {"email is required"}},
+		})
+		res["code"] = "INVALID_ARGUMENT"
+		helper.RespondWithError(c, http.StatusBadRequest, res)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", nil)
+	req.Header.Set(constant.HeaderRequestId, "req-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var problem map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	assert.Equal(t, map[string]any{
+		"type":       "about:blank",
+		"title":      "Bad Request",
+		"status":     float64(http.StatusBadRequest),
+		"detail":     constant.MessageBadRequest,
+		"instance":   "/auth/register",
+		"request_id": "req-1",
+		"code":       "INVALID_ARGUMENT",
+		"errors":     map[string]any{"email": []any{"email is required"}},
+	}, problem)
+}
@@ -19,7 +19,7 @@ func resetMetrics() {
 	prometheus.TotalRequests.Reset()
 	prometheus.ErrorCount.Reset()
 	prometheus.RequestDuration.Reset()
-	prometheus.ServiceHealth.Set(0)
+	prometheus.ServiceHealth.Reset()
 }
 
 func TestPrometheusMiddleware(t *testing.T) {
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/ratelimit"
+)
+
+type RateLimitKeyFunc func(c *gin.Context) string
+
+func RateLimitKeyByIP(c *gin.Context) string {
+	return constant.RateLimitKeyByIP + ":" + c.ClientIP()
+}
+
+// RateLimitKeyByUser keys by the user set by VerifyTokenMiddleware and falls
+// back to the client IP for unauthenticated requests.
+func RateLimitKeyByUser(c *gin.Context) string {
+	if user, exists := c.Get(constant.AuthUser); exists {
+		if u, ok := user.(*authpb.User); ok {
+			return constant.RateLimitKeyByUser + ":" + strconv.Itoa(int(u.Id))
+		}
+	}
+
+	return RateLimitKeyByIP(c)
+}
+
+func RateLimitMiddleware(store ratelimit.Store, scope string, limit ratelimit.Limit, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := store.Take(c.Request.Context(), scope+":"+keyFunc(c), limit)
+		if err != nil {
+			// Fail open, an unavailable store should not take the gateway down with it.
+			logger.Ctx(c.Request.Context()).Error().Str("scope", scope).Err(err).Msg("Rate limit store failed")
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		header.Set("RateLimit-Reset", formatSeconds(res.ResetAfter))
+
+		if !res.Allowed {
+			header.Set("Retry-After", formatSeconds(res.RetryAfter))
+			helper.RespondWithError(c, http.StatusTooManyRequests, helper.PrepareResponse(constant.MessageTooManyRequests, gin.H{}))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,108 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limit := ratelimit.Limit{Algorithm: ratelimit.AlgorithmTokenBucket, Requests: 2, Window: time.Minute}
+
+	tests := []struct {
+		name           string
+		store          ratelimit.Store
+		keyFunc        middleware.RateLimitKeyFunc
+		requests       []string // client IPs
+		user           bool
+		expectedStatus []int
+	}{
+		{
+			name:           "limits by client ip",
+			store:          ratelimit.NewMemoryStore(),
+			keyFunc:        middleware.RateLimitKeyByIP,
+			requests:       []string{"1.1.1.1", "1.1.1.1", "1.1.1.1", "2.2.2.2"},
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+		{
+			name:           "limits by authenticated user across ips",
+			store:          ratelimit.NewMemoryStore(),
+			keyFunc:        middleware.RateLimitKeyByUser,
+			requests:       []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"},
+			user:           true,
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:           "user key falls back to client ip",
+			store:          ratelimit.NewMemoryStore(),
+			keyFunc:        middleware.RateLimitKeyByUser,
+			requests:       []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"},
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:           "store failure lets requests through",
+			store:          failingStore{},
+			keyFunc:        middleware.RateLimitKeyByIP,
+			requests:       []string{"1.1.1.1", "1.1.1.1", "1.1.1.1"},
+			expectedStatus: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			if tt.user {
+				r.Use(func(c *gin.Context) {
+					c.Set(constant.AuthUser, dummyUser)
+				})
+			}
+			r.Use(middleware.RateLimitMiddleware(tt.store, "test", limit, tt.keyFunc))
+			r.GET("/test", func(c *gin.Context) {
+				c.String(http.StatusOK, "ok")
+			})
+
+			for i, ip := range tt.requests {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.RemoteAddr = ip + ":1234"
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.expectedStatus[i], w.Code, "request %d", i)
+
+				if _, ok := tt.store.(failingStore); ok {
+					assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+					continue
+				}
+
+				assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+				assert.NotEmpty(t, w.Header().Get("RateLimit-Remaining"))
+				assert.NotEmpty(t, w.Header().Get("RateLimit-Reset"))
+
+				if w.Code == http.StatusTooManyRequests {
+					assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+					assert.Equal(t, "30", w.Header().Get("Retry-After"))
+					assert.JSONEq(t, `{"message":"`+constant.MessageTooManyRequests+`","data":{}}`, w.Body.String())
+				} else {
+					assert.Empty(t, w.Header().Get("Retry-After"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+)
+
+// RequestIDMiddleware reuses the X-Request-ID sent by the client or generates
+// one, attaches it to the request context and echoes it in the response.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(constant.HeaderRequestId)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(constant.HeaderRequestId, id)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		expectReuse bool
+	}{
+		{
+			name:        "reuses the client's request id",
+			header:      "client-request-1",
+			expectReuse: true,
+		},
+		{
+			name:        "generates a request id when none is sent",
+			header:      "",
+			expectReuse: false,
+		},
+		{
+			name:        "replaces an invalid request id",
+			header:      "has spaces",
+			expectReuse: false,
+		},
+		{
+			name:        "replaces a too long request id",
+			header:      strings.Repeat("a", 129),
+			expectReuse: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log.Logger = zerolog.New(&buf)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(middleware.RequestIDMiddleware(), middleware.ZerologMiddleware())
+
+			var fromContext string
+			r.GET("/test", func(c *gin.Context) {
+				fromContext = requestid.FromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(constant.HeaderRequestId, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(constant.HeaderRequestId)
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, fromContext)
+			assert.Equal(t, tt.expectReuse, id == tt.header)
+			assert.Contains(t, buf.String(), `"request_id":"`+id+`"`)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/prometheus"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/responsecache"
+)
+
+// cachedHeaders are the response headers replayed on a hit, everything else
+// is specific to the request that filled the cache.
+var cachedHeaders = []string{"Content-Type"}
+
+// ResponseCacheMiddleware serves successful GET responses from store for ttl.
+// Responses carry ETag and Last-Modified headers and matching conditional
+// requests get a 304. "Cache-Control: no-cache" skips the lookup and refreshes
+// the entry, "no-store" bypasses the cache entirely.
+func ResponseCacheMiddleware(store responsecache.Store, ttl time.Duration, tags []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		// responses are localized, see LocaleMiddleware
+		key := c.Request.Method + " " + c.Request.URL.Path + "?" + c.Request.URL.Query().Encode() + " " + i18n.FromContext(c.Request.Context())
+		directives := c.GetHeader("Cache-Control")
+
+		if strings.Contains(directives, "no-store") {
+			prometheus.ResponseCacheRequests.WithLabelValues(route, "bypass").Inc()
+			c.Next()
+			return
+		}
+
+		if !strings.Contains(directives, "no-cache") {
+			entry, ok, err := store.Get(c.Request.Context(), key)
+			if err != nil {
+				logger.Ctx(c.Request.Context()).Error().Str("key", key).Err(err).Msg("Response cache lookup failed")
+			}
+			if ok {
+				prometheus.ResponseCacheRequests.WithLabelValues(route, "hit").Inc()
+				c.Header("X-Cache", "HIT")
+				writeEntry(c, entry)
+				c.Abort()
+				return
+			}
+		}
+		prometheus.ResponseCacheRequests.WithLabelValues(route, "miss").Inc()
+
+		// taken before the handler reads the backend, an invalidation while it
+		// runs then keeps its possibly stale response out of the cache
+		versions, err := store.Versions(c.Request.Context(), tags)
+		if err != nil {
+			logger.Ctx(c.Request.Context()).Error().Str("key", key).Err(err).Msg("Response cache lookup failed")
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.status != http.StatusOK {
+			w.flush()
+			return
+		}
+
+		entry := &responsecache.Entry{
+			Status:       w.status,
+			Header:       http.Header{},
+			Body:         w.body.Bytes(),
+			ETag:         etag(w.body.Bytes()),
+			LastModified: time.Now().UTC().Truncate(time.Second),
+			Tags:         tags,
+		}
+		for _, h := range cachedHeaders {
+			if v := w.Header().Values(h); len(v) > 0 {
+				entry.Header[h] = v
+			}
+		}
+
+		if versions != nil {
+			if err := store.Set(c.Request.Context(), key, entry, ttl, versions); err != nil {
+				logger.Ctx(c.Request.Context()).Error().Str("key", key).Err(err).Msg("Response cache store failed")
+			}
+		}
+
+		c.Header("X-Cache", "MISS")
+		writeEntry(c, entry)
+	}
+}
+
+// InvalidateResponseCacheMiddleware drops the cached responses tagged with
+// tags once the route has succeeded.
+func InvalidateResponseCacheMiddleware(store responsecache.Store, tags []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Status() < http.StatusOK || c.Writer.Status() >= http.StatusMultipleChoices {
+			return
+		}
+
+		for _, tag := range tags {
+			if err := store.Invalidate(c.Request.Context(), tag); err != nil {
+				logger.Ctx(c.Request.Context()).Error().Str("tag", tag).Err(err).Msg("Response cache invalidation failed")
+			}
+		}
+	}
+}
+
+func writeEntry(c *gin.Context, entry *responsecache.Entry) {
+	c.Header("ETag", entry.ETag)
+	c.Header("Last-Modified", entry.LastModified.Format(http.TimeFormat))
+
+	if notModified(c.Request, entry) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	for h, v := range entry.Header {
+		c.Writer.Header()[h] = v
+	}
+	c.Status(entry.Status)
+	_, _ = c.Writer.Write(entry.Body)
+}
+
+// notModified follows RFC 9110, If-Modified-Since is only considered when
+// there is no If-None-Match.
+func notModified(r *http.Request, entry *responsecache.Entry) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == entry.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		return !entry.LastModified.After(since)
+	}
+
+	return false
+}
+
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"%x"`, sum[:16])
+}
+
+// bufferedWriter holds the response back so it can be stored and its ETag
+// set before anything is sent to the client.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	body    bytes.Buffer
+	written bool
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	} else {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
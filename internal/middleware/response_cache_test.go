@@ -0,0 +1,177 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/responsecache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponseCacheRouter(store responsecache.Store, calls *int, status *int) *gin.Engine {
+	router := gin.New()
+	router.GET("/videos", middleware.ResponseCacheMiddleware(store, time.Minute, []string{"videos"}), func(c *gin.Context) {
+		*calls++
+		c.JSON(*status, gin.H{"calls": *calls})
+	})
+	router.POST("/videos", middleware.InvalidateResponseCacheMiddleware(store, []string{"videos"}), func(c *gin.Context) {
+		c.Status(*status)
+	})
+
+	return router
+}
+
+func doRequest(router *gin.Engine, method string, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestResponseCacheMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls, status := 0, http.StatusOK
+	router := newResponseCacheRouter(responsecache.NewMemoryStore(10), &calls, &status)
+
+	w := doRequest(router, http.MethodGet, "/videos?page=1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 1}`, w.Body.String())
+	etag := w.Header().Get("ETag")
+	lastModified := w.Header().Get("Last-Modified")
+	assert.NotEmpty(t, etag)
+	assert.NotEmpty(t, lastModified)
+
+	w = doRequest(router, http.MethodGet, "/videos?page=1", nil)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 1}`, w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	w = doRequest(router, http.MethodGet, "/videos?page=2", nil)
+	assert.JSONEq(t, `{"calls": 2}`, w.Body.String(), "query string is part of the key")
+
+	w = doRequest(router, http.MethodGet, "/videos?page=1", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = doRequest(router, http.MethodGet, "/videos?page=1", http.Header{"If-None-Match": {`"other"`}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doRequest(router, http.MethodGet, "/videos?page=1", http.Header{"If-Modified-Since": {lastModified}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = doRequest(router, http.MethodGet, "/videos?page=1", http.Header{"Cache-Control": {"no-cache"}})
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 3}`, w.Body.String())
+
+	w = doRequest(router, http.MethodGet, "/videos?page=1", nil)
+	assert.JSONEq(t, `{"calls": 3}`, w.Body.String(), "no-cache should refresh the entry")
+
+	w = doRequest(router, http.MethodGet, "/videos?page=1", http.Header{"Cache-Control": {"no-store"}})
+	assert.Empty(t, w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 4}`, w.Body.String())
+}
+
+func TestResponseCacheMiddleware_KeyedByLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls, status := 0, http.StatusOK
+	router := gin.New()
+	router.Use(middleware.LocaleMiddleware("en"))
+	router.GET("/videos", middleware.ResponseCacheMiddleware(responsecache.NewMemoryStore(10), time.Minute, nil), func(c *gin.Context) {
+		calls++
+		c.JSON(status, gin.H{"calls": calls})
+	})
+
+	w := doRequest(router, http.MethodGet, "/videos", http.Header{"Accept-Language": {"es"}})
+	assert.JSONEq(t, `{"calls": 1}`, w.Body.String())
+
+	w = doRequest(router, http.MethodGet, "/videos", http.Header{"Accept-Language": {"fr"}})
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 2}`, w.Body.String())
+
+	w = doRequest(router, http.MethodGet, "/videos", http.Header{"Accept-Language": {"es-MX"}})
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 1}`, w.Body.String())
+}
+
+func TestResponseCacheMiddleware_SkipsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls, status := 0, http.StatusServiceUnavailable
+	router := newResponseCacheRouter(responsecache.NewMemoryStore(10), &calls, &status)
+
+	w := doRequest(router, http.MethodGet, "/videos", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"calls": 1}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	doRequest(router, http.MethodGet, "/videos", nil)
+	assert.Equal(t, 2, calls)
+}
+
+func TestInvalidateResponseCacheMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls, status := 0, http.StatusOK
+	router := newResponseCacheRouter(responsecache.NewMemoryStore(10), &calls, &status)
+
+	doRequest(router, http.MethodGet, "/videos", nil)
+
+	status = http.StatusBadRequest
+	doRequest(router, http.MethodPost, "/videos", nil)
+	status = http.StatusOK
+	w := doRequest(router, http.MethodGet, "/videos", nil)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"), "failed requests should not invalidate")
+
+	doRequest(router, http.MethodPost, "/videos", nil)
+	w = doRequest(router, http.MethodGet, "/videos", nil)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 2}`, w.Body.String())
+}
+
+func TestResponseCacheMiddleware_InvalidatedWhileHandling(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := responsecache.NewMemoryStore(10)
+	calls := 0
+
+	router := gin.New()
+	router.GET("/videos", middleware.ResponseCacheMiddleware(store, time.Minute, []string{"videos"}), func(c *gin.Context) {
+		calls++
+		body := gin.H{"calls": calls}
+		if calls == 1 {
+			// a webhook publishes a video after the backend was read
+			w := doRequest(router, http.MethodPost, "/webhook", nil)
+			require.Equal(t, http.StatusOK, w.Code)
+		}
+		c.JSON(http.StatusOK, body)
+	})
+	router.POST("/webhook", middleware.InvalidateResponseCacheMiddleware(store, []string{"videos"}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := doRequest(router, http.MethodGet, "/videos", nil)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 1}`, w.Body.String())
+
+	w = doRequest(router, http.MethodGet, "/videos", nil)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"), "the stale response must not be cached")
+	assert.JSONEq(t, `{"calls": 2}`, w.Body.String())
+
+	w = doRequest(router, http.MethodGet, "/videos", nil)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"calls": 2}`, w.Body.String())
+}
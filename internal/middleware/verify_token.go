@@ -2,34 +2,103 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/jwt"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
 	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type VerifyTokenFunc func(ctx context.Context, in *authpb.VerifyTokenRequest, token string) (*authpb.VerifyTokenResponse, error)
 
+// RevokedFunc reports whether the bearer token was logged out.
+type RevokedFunc func(token string) bool
+
 func VerifyTokenMiddleware(verifyToken VerifyTokenFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var h types.AuthorizationHeader
 		if err := c.ShouldBindHeader(&h); err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, helper.PrepareResponse(constant.MessageUnauthorized, gin.H{}))
+			helper.RespondWithError(c, http.StatusUnauthorized, helper.PrepareResponse(constant.MessageUnauthorized, gin.H{}))
 			return
 		}
 
 		res, err := verifyToken(c.Request.Context(), &authpb.VerifyTokenRequest{}, h.Token)
 		if err != nil {
 			status, res := helper.PrepareResponseFromGRPCError(err, &types.VerifyTokenValidationError{})
-			c.AbortWithStatusJSON(status, res)
+			helper.RespondWithError(c, status, res)
 			return
 		}
 
 		c.Set(constant.AuthUser, res.Data.User)
 		c.Set(constant.GRPCHeaderAuthorization, h)
+		c.Request = c.Request.WithContext(logger.WithFields(c.Request.Context(), map[string]any{
+			"user_id": res.Data.User.Id,
+		}))
 		c.Next()
 	}
 }
+
+// LocalVerifyToken verifies the bearer token signature and claims in the
+// gateway instead of calling the authentication service. Tokens that cannot be
+// checked locally (e.g. unknown signing key) are passed to fallback when set.
+// Tokens revoked reports as logged out are rejected, the authentication
+// service is not asked.
+func LocalVerifyToken(verifier *jwt.Verifier, fallback VerifyTokenFunc, revoked RevokedFunc) VerifyTokenFunc {
+	return func(ctx context.Context, in *authpb.VerifyTokenRequest, token string) (*authpb.VerifyTokenResponse, error) {
+		claims, err := verifier.Verify(ctx, jwt.TrimBearer(token))
+		if err != nil {
+			if fallback != nil && errors.Is(err, jwt.ErrUnverifiable) {
+				logger.Ctx(ctx).Warn().Err(err).Msg("Falling back to remote token verification")
+				return fallback(ctx, in, token)
+			}
+			return nil, status.Error(codes.Unauthenticated, constant.MessageUnauthorized)
+		}
+
+		if revoked != nil && revoked(token) {
+			return nil, status.Error(codes.Unauthenticated, constant.MessageUnauthorized)
+		}
+
+		user, err := userFromClaims(claims)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, constant.MessageUnauthorized)
+		}
+
+		return &authpb.VerifyTokenResponse{
+			Message: constant.MessageOK,
+			Data:    &authpb.VerifyTokenResponseData{User: user},
+		}, nil
+	}
+}
+
+func userFromClaims(claims *jwt.Claims) (*authpb.User, error) {
+	id, err := strconv.ParseInt(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &authpb.User{
+		Id:    int32(id),
+		Name:  claims.String("name"),
+		Email: claims.String("email"),
+	}
+
+	if image := claims.String("image"); image != "" {
+		user.Image = &image
+	}
+	if createdAt := claims.String("created_at"); createdAt != "" {
+		user.CreatedAt = &createdAt
+	}
+	if updatedAt := claims.String("updated_at"); updatedAt != "" {
+		user.UpdatedAt = &updatedAt
+	}
+
+	return user, nil
+}
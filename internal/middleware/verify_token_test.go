@@ -2,6 +2,10 @@ package middleware_test
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -10,9 +14,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/jwt"
 	"github.com/sagarmaheshwary/microservices-api-gateway/internal/middleware"
 	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var now = time.Now().String()
@@ -109,3 +117,108 @@ func TestVerifyTokenMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func signToken(t *testing.T, alg string, secret []byte, claims map[string]any) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(map[string]any{"alg": alg, "typ": "JWT"}) + "." + encode(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestLocalVerifyToken(t *testing.T) {
+	secret := []byte("secret")
+	verifier := jwt.NewVerifier(jwt.Options{Secret: secret})
+	exp := time.Now().Add(time.Hour).Unix()
+
+	validToken := signToken(t, "HS256", secret, map[string]any{
+		"sub":        "7",
+		"name":       "name",
+		"email":      "name@gmail.com",
+		"created_at": "2025-01-01",
+		"exp":        exp,
+	})
+
+	var fallbackCalls int
+	fallback := func(ctx context.Context, in *authpb.VerifyTokenRequest, token string) (*authpb.VerifyTokenResponse, error) {
+		fallbackCalls++
+		return mockVerifyTokenSuccess(ctx, in, token)
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		fallback       middleware.VerifyTokenFunc
+		expectUserId   int32
+		expectErr      bool
+		expectFallback int
+	}{
+		{
+			name:         "valid token",
+			token:        "Bearer " + validToken,
+			fallback:     fallback,
+			expectUserId: 7,
+		},
+		{
+			name:      "bad signature is not sent to the fallback",
+			token:     "Bearer " + signToken(t, "HS256", []byte("other"), map[string]any{"sub": "7", "exp": exp}),
+			fallback:  fallback,
+			expectErr: true,
+		},
+		{
+			name:           "unverifiable token is sent to the fallback",
+			token:          "Bearer " + signToken(t, "RS256", secret, map[string]any{"sub": "7", "exp": exp}),
+			fallback:       fallback,
+			expectUserId:   dummyUser.Id,
+			expectFallback: 1,
+		},
+		{
+			name:      "unverifiable token without fallback",
+			token:     "Bearer " + signToken(t, "RS256", secret, map[string]any{"sub": "7", "exp": exp}),
+			expectErr: true,
+		},
+		{
+			name:      "non numeric subject",
+			token:     "Bearer " + signToken(t, "HS256", secret, map[string]any{"sub": "abc", "exp": exp}),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallbackCalls = 0
+
+			res, err := middleware.LocalVerifyToken(verifier, tt.fallback, nil)(context.Background(), &authpb.VerifyTokenRequest{}, tt.token)
+
+			assert.Equal(t, tt.expectFallback, fallbackCalls)
+			if tt.expectErr {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectUserId, res.Data.User.Id)
+		})
+	}
+
+	res, err := middleware.LocalVerifyToken(verifier, nil, nil)(context.Background(), &authpb.VerifyTokenRequest{}, validToken)
+	require.NoError(t, err)
+	assert.Equal(t, "name@gmail.com", res.Data.User.Email)
+	require.NotNil(t, res.Data.User.CreatedAt)
+	assert.Equal(t, "2025-01-01", *res.Data.User.CreatedAt)
+	assert.Nil(t, res.Data.User.Image)
+
+	fallbackCalls = 0
+	revoked := func(token string) bool { return token == validToken }
+	_, err = middleware.LocalVerifyToken(verifier, fallback, revoked)(context.Background(), &authpb.VerifyTokenRequest{}, validToken)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "logged out tokens are rejected")
+	assert.Zero(t, fallbackCalls)
+}
@@ -0,0 +1,164 @@
+(function () {
+  "use strict";
+
+  var root = document.getElementById("docs");
+  var components = {};
+
+  function el(tag, className, text) {
+    var node = document.createElement(tag);
+    if (className) node.className = className;
+    if (text !== undefined) node.textContent = text;
+    return node;
+  }
+
+  function resolve(schema) {
+    if (schema && schema.$ref) {
+      var name = schema.$ref.replace("#/components/schemas/", "");
+      return { name: name, schema: components[name] || {} };
+    }
+    return { name: "", schema: schema || {} };
+  }
+
+  function describeType(schema) {
+    var r = resolve(schema);
+    var s = r.schema;
+    if (r.name) return r.name;
+    if (s.type === "array") return describeType(s.items) + "[]";
+    if (s.type === "object" && s.additionalProperties) return "map<string, " + describeType(s.additionalProperties) + ">";
+    return (s.type || "any") + (s.format ? " (" + s.format + ")" : "");
+  }
+
+  function constraints(schema) {
+    var s = resolve(schema).schema;
+    var out = [];
+    if (s.minLength !== undefined) out.push("min length " + s.minLength);
+    if (s.maxLength !== undefined) out.push("max length " + s.maxLength);
+    if (s.minimum !== undefined) out.push("min " + s.minimum);
+    if (s.maximum !== undefined) out.push("max " + s.maximum);
+    if (s.pattern) out.push("pattern " + s.pattern);
+    if (s.enum) out.push("one of " + s.enum.join(", "));
+    return out.join(", ");
+  }
+
+  // renderSchema lists the properties of schema, nested objects are expanded
+  // once per branch so recursive messages terminate.
+  function renderSchema(schema, seen) {
+    var r = resolve(schema);
+    var s = r.schema;
+    seen = seen || [];
+
+    if (s.type === "array") return renderSchema(s.items, seen);
+    if (!s.properties || (r.name && seen.indexOf(r.name) !== -1)) return null;
+    if (r.name) seen = seen.concat([r.name]);
+
+    var list = el("ul", "schema");
+    Object.keys(s.properties).sort().forEach(function (name) {
+      var prop = s.properties[name];
+      var item = el("li");
+      item.appendChild(el("code", "", name));
+      if ((s.required || []).indexOf(name) !== -1) item.appendChild(el("span", "required", " required"));
+      item.appendChild(el("span", "type", " " + describeType(prop)));
+
+      var notes = [constraints(prop), resolve(prop).schema.description].filter(Boolean).join(". ");
+      if (notes) item.appendChild(el("div", "notes", notes));
+
+      var nested = renderSchema(prop, seen);
+      if (nested) item.appendChild(nested);
+      list.appendChild(item);
+    });
+    return list;
+  }
+
+  function renderContent(content) {
+    var box = el("div");
+    Object.keys(content || {}).forEach(function (type) {
+      box.appendChild(el("div", "media", type + ": " + describeType(content[type].schema)));
+      var schema = renderSchema(content[type].schema);
+      if (schema) box.appendChild(schema);
+    });
+    return box;
+  }
+
+  function renderOperation(path, method, op) {
+    var section = el("details", "operation");
+    var summary = el("summary");
+    summary.appendChild(el("span", "method " + method, method.toUpperCase()));
+    summary.appendChild(el("code", "path", path));
+    if (op.summary) summary.appendChild(el("span", "summary", op.summary));
+    if (op.security) summary.appendChild(el("span", "auth", "bearer token"));
+    section.appendChild(summary);
+
+    if (op.description) section.appendChild(el("p", "", op.description));
+
+    if (op.parameters && op.parameters.length) {
+      section.appendChild(el("h4", "", "Parameters"));
+      var params = el("ul", "schema");
+      op.parameters.forEach(function (p) {
+        var item = el("li");
+        item.appendChild(el("code", "", p.name));
+        item.appendChild(el("span", "type", " " + p.in + ", " + describeType(p.schema)));
+        if (p.required) item.appendChild(el("span", "required", " required"));
+        var notes = [constraints(p.schema), p.description].filter(Boolean).join(". ");
+        if (notes) item.appendChild(el("div", "notes", notes));
+        params.appendChild(item);
+      });
+      section.appendChild(params);
+    }
+
+    if (op.requestBody) {
+      section.appendChild(el("h4", "", "Request body"));
+      section.appendChild(renderContent(op.requestBody.content));
+    }
+
+    section.appendChild(el("h4", "", "Responses"));
+    Object.keys(op.responses || {}).sort().forEach(function (status) {
+      var res = op.responses[status];
+      var item = el("div", "response");
+      item.appendChild(el("strong", "", status + " " + res.description));
+      Object.keys(res.headers || {}).forEach(function (name) {
+        item.appendChild(el("div", "notes", "header " + name + ": " + (res.headers[name].description || "")));
+      });
+      item.appendChild(renderContent(res.content));
+      section.appendChild(item);
+    });
+
+    return section;
+  }
+
+  function render(doc) {
+    components = (doc.components && doc.components.schemas) || {};
+    root.textContent = "";
+
+    root.appendChild(el("h1", "", doc.info.title + " " + doc.info.version));
+    if (doc.info.description) root.appendChild(el("p", "", doc.info.description));
+    var link = el("a", "", "OpenAPI " + doc.openapi + " document");
+    link.href = root.dataset.specUrl;
+    root.appendChild(link);
+
+    var byTag = {};
+    Object.keys(doc.paths).sort().forEach(function (path) {
+      Object.keys(doc.paths[path]).forEach(function (method) {
+        var op = doc.paths[path][method];
+        var tag = (op.tags && op.tags[0]) || "default";
+        (byTag[tag] = byTag[tag] || []).push(renderOperation(path, method, op));
+      });
+    });
+
+    Object.keys(byTag).sort().forEach(function (tag) {
+      root.appendChild(el("h2", "", tag));
+      byTag[tag].forEach(function (op) {
+        root.appendChild(op);
+      });
+    });
+  }
+
+  fetch(root.dataset.specUrl, { headers: { Accept: "application/json" } })
+    .then(function (res) {
+      if (!res.ok) throw new Error("HTTP " + res.status);
+      return res.json();
+    })
+    .then(render)
+    .catch(function (err) {
+      root.textContent = "Failed to load the OpenAPI document: " + err.message;
+    });
+})();
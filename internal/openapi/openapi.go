@@ -0,0 +1,330 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeProblemJSON = "application/problem+json"
+)
+
+// Operation describes what the handler of a route reads and writes.
+type Operation struct {
+	Summary     string
+	Description string
+	// Parameters are the query and header parameters, path parameters are
+	// taken from the route unless listed here.
+	Parameters  []*Parameter
+	RequestBody *Schema
+	// Response is the body of a successful response, sent with Status and
+	// ContentType, 200 and application/json by default.
+	Response    *Schema
+	Status      int
+	ContentType string
+	// Errors describes the field errors of a 400 response.
+	Errors *Schema
+	// Responses lists other statuses the handler itself answers with.
+	Responses map[int]*Schema
+}
+
+// Route is an entry of the route table with the operation of its handler.
+type Route struct {
+	Method string
+	// Path uses gin's syntax, e.g. "/videos/:id".
+	Path      string
+	Service   string
+	RPC       string
+	Auth      bool
+	RateLimit bool
+	// Backend is false for routes answered by the gateway itself.
+	Backend   bool
+	Operation Operation
+}
+
+// RPC describes a route at path transcoded onto a unary rpc, see
+// internal/transcoder. Fields of the input message are read from the path
+// params, then from the query string of GET and DELETE routes and from the
+// body of the others. The output message is the response.
+func RPC(method protoreflect.MethodDescriptor, httpMethod string, path string) Operation {
+	op := Operation{
+		Summary:  string(method.FullName()),
+		Response: messageSchema(method.Output()),
+	}
+
+	inPath := map[string]bool{}
+	for _, m := range pathParam.FindAllStringSubmatch(path, -1) {
+		inPath[m[1]] = true
+	}
+
+	fields := method.Input().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := string(fd.Name())
+
+		switch {
+		case inPath[name]:
+			op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: protoFieldSchema(fd)})
+		case httpMethod != http.MethodGet && httpMethod != http.MethodDelete:
+		case fd.Kind() == protoreflect.MessageKind || fd.IsMap():
+		default:
+			op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "query", Schema: protoFieldSchema(fd)})
+		}
+	}
+
+	if httpMethod != http.MethodGet && httpMethod != http.MethodDelete {
+		op.RequestBody = messageSchema(method.Input())
+		// bodies over TRANSCODER_MAX_BODY_BYTES are not read
+		op.Responses = map[int]*Schema{http.StatusRequestEntityTooLarge: {Ref: "#/components/schemas/Error"}}
+	}
+
+	return op
+}
+
+// Bound describes a route at path served by a handler that binds input, a
+// pointer to a struct, and answers with the output of method, e.g. a handler
+// in internal/handler. Like RPC, input is read from the query string of GET
+// and DELETE routes and from the body of the others, path params are typed by
+// the fields of the input message. Either may be nil.
+func Bound(method protoreflect.MethodDescriptor, input any, httpMethod string, path string) Operation {
+	var op Operation
+	if method != nil {
+		rpc := RPC(method, httpMethod, path)
+		op.Summary, op.Response = rpc.Summary, rpc.Response
+		for _, p := range rpc.Parameters {
+			if p.In == "path" {
+				op.Parameters = append(op.Parameters, p)
+			}
+		}
+	}
+
+	switch {
+	case input == nil:
+	case httpMethod == http.MethodGet || httpMethod == http.MethodDelete:
+		op.Parameters = append(op.Parameters, Parameters("query", input)...)
+	default:
+		op.RequestBody = Struct(input)
+	}
+
+	return op
+}
+
+var pathParam = regexp.MustCompile(`[:*](\w+)`)
+
+// Generate builds the document of routes.
+func Generate(info Info, routes []Route) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Error":   errorSchema(nil),
+				"Problem": problemSchema(),
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	tags := map[string]bool{}
+	for _, rt := range routes {
+		path := pathParam.ReplaceAllString(rt.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(rt.Method)] = operation(rt)
+
+		if !tags[rt.Service] {
+			tags[rt.Service] = true
+			doc.Tags = append(doc.Tags, Tag{Name: rt.Service})
+		}
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	for _, item := range doc.Paths {
+		for _, op := range item {
+			collectOperation(doc.Components.Schemas, op)
+		}
+	}
+
+	return doc
+}
+
+func operation(rt Route) *OperationObject {
+	op := rt.Operation
+	o := &OperationObject{
+		OperationID: rt.Service + "." + rt.RPC,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        []string{rt.Service},
+		Responses:   map[string]*Response{},
+	}
+
+	declared := map[string]bool{}
+	for _, p := range op.Parameters {
+		declared[p.In+":"+p.Name] = true
+	}
+	for _, m := range pathParam.FindAllStringSubmatch(rt.Path, -1) {
+		if !declared["path:"+m[1]] {
+			o.Parameters = append(o.Parameters, &Parameter{Name: m[1], In: "path", Required: true, Schema: String()})
+		}
+	}
+	o.Parameters = append(o.Parameters, op.Parameters...)
+	hasInput := op.RequestBody != nil || len(o.Parameters) > 0
+
+	o.Parameters = append(o.Parameters, &Parameter{
+		Name:        "Accept-Language",
+		In:          "header",
+		Description: "Locale of the gateway's messages, e.g. \"es\".",
+		Schema:      String(),
+	})
+
+	if op.RequestBody != nil {
+		o.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{ContentTypeJSON: {Schema: op.RequestBody}},
+		}
+	}
+
+	status, contentType := op.Status, op.ContentType
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if contentType == "" {
+		contentType = ContentTypeJSON
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if op.Response != nil {
+		success.Content = map[string]*MediaType{contentType: {Schema: op.Response}}
+	}
+	o.Responses[strconv.Itoa(status)] = success
+
+	for code, schema := range op.Responses {
+		o.Responses[strconv.Itoa(code)] = &Response{
+			Description: http.StatusText(code),
+			Content:     map[string]*MediaType{ContentTypeJSON: {Schema: schema}},
+		}
+	}
+
+	if op.Errors != nil || hasInput {
+		o.Responses["400"] = errorResponse(http.StatusBadRequest, op.Errors)
+	}
+	if rt.Auth {
+		o.Security = []map[string][]string{{"bearerAuth": {}}}
+		o.Responses["401"] = errorResponse(http.StatusUnauthorized, nil)
+	}
+	if rt.RateLimit {
+		res := errorResponse(http.StatusTooManyRequests, nil)
+		res.Headers = map[string]*Header{
+			"Retry-After": {Description: "Seconds until the limit resets.", Schema: Integer()},
+		}
+		o.Responses["429"] = res
+	}
+	if rt.Backend {
+		o.Responses["503"] = errorResponse(http.StatusServiceUnavailable, nil)
+	}
+	o.Responses["default"] = errorResponse(0, nil)
+
+	return o
+}
+
+// errorResponse is written by helper.RespondWithError, as the {message, data}
+// envelope or as a problem document.
+func errorResponse(status int, fieldErrors *Schema) *Response {
+	description := "Error"
+	if status != 0 {
+		description = http.StatusText(status)
+	}
+
+	schema := &Schema{Ref: "#/components/schemas/Error"}
+	if fieldErrors != nil {
+		schema = errorSchema(fieldErrors)
+	}
+
+	return &Response{
+		Description: description,
+		Content: map[string]*MediaType{
+			ContentTypeJSON:        {Schema: schema},
+			ContentTypeProblemJSON: {Schema: &Schema{Ref: "#/components/schemas/Problem"}},
+		},
+	}
+}
+
+func errorSchema(fieldErrors *Schema) *Schema {
+	data := &Schema{Type: "object", Description: "Details such as retry_after or the reason of a backend error."}
+	if fieldErrors != nil {
+		data = Object(map[string]*Schema{"errors": fieldErrors}, "errors")
+	}
+
+	s := Envelope(data)
+	s.Properties["code"] = &Schema{Type: "string", Description: "gRPC status of a backend error, e.g. \"NOT_FOUND\"."}
+
+	return s
+}
+
+// problemSchema is an RFC 9457 problem document, see helper.NewProblem.
+func problemSchema() *Schema {
+	s := Object(map[string]*Schema{
+		"type":       String(),
+		"title":      String(),
+		"status":     Integer(),
+		"detail":     String(),
+		"instance":   String(),
+		"code":       String(),
+		"request_id": String(),
+		"errors":     {Type: "object", AdditionalProperties: Array(String())},
+	}, "type", "title", "status")
+	s.AdditionalProperties = &Schema{}
+
+	return s
+}
+
+func collectOperation(components map[string]*Schema, op *OperationObject) {
+	for _, p := range op.Parameters {
+		collect(components, p.Schema)
+	}
+	if op.RequestBody != nil {
+		for _, mt := range op.RequestBody.Content {
+			collect(components, mt.Schema)
+		}
+	}
+	for _, res := range op.Responses {
+		for _, mt := range res.Content {
+			collect(components, mt.Schema)
+		}
+	}
+}
+
+// collect adds the components s refers to.
+func collect(components map[string]*Schema, s *Schema) {
+	if s == nil {
+		return
+	}
+
+	if s.component != "" {
+		if _, ok := components[s.component]; ok {
+			return
+		}
+
+		// a placeholder stops recursive messages
+		components[s.component] = &Schema{}
+		definition := s.define()
+		components[s.component] = definition
+		collect(components, definition)
+		return
+	}
+
+	for _, p := range s.Properties {
+		collect(components, p)
+	}
+	collect(components, s.Items)
+	collect(components, s.AdditionalProperties)
+}
@@ -0,0 +1,298 @@
+package openapi_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/openapi"
+	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
+	uploadpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/upload/upload"
+	videocatalogpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/video_catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// generate builds a document of routes and decodes it like a client would.
+func generate(t *testing.T, routes ...openapi.Route) map[string]any {
+	t.Helper()
+
+	b, err := json.Marshal(openapi.Generate(openapi.Info{Title: "test", Version: "1"}, routes))
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(b, &doc))
+
+	return doc
+}
+
+// get walks doc along keys.
+func get(t *testing.T, doc any, keys ...string) any {
+	t.Helper()
+
+	for _, key := range keys {
+		m, ok := doc.(map[string]any)
+		require.True(t, ok, "%q is not an object", key)
+		doc, ok = m[key]
+		require.True(t, ok, "%q is missing", key)
+	}
+
+	return doc
+}
+
+func method(t *testing.T, m interface {
+	ProtoReflect() protoreflect.Message
+}, service, rpc string) protoreflect.MethodDescriptor {
+	t.Helper()
+
+	md := m.ProtoReflect().Descriptor().ParentFile().Services().ByName(protoreflect.Name(service)).Methods().ByName(protoreflect.Name(rpc))
+	require.NotNil(t, md)
+
+	return md
+}
+
+func TestStruct_BindingTags(t *testing.T) {
+	doc := generate(t,
+		openapi.Route{Method: http.MethodPost, Path: "/auth/register", Service: "authentication", RPC: "Register", Operation: openapi.Operation{RequestBody: openapi.Struct(&types.RegisterInput{})}},
+		openapi.Route{Method: http.MethodPost, Path: "/webhook", Service: "upload", RPC: "UploadedWebhook", Operation: openapi.Operation{RequestBody: openapi.Struct(&types.UploadedWebhookInput{})}},
+	)
+	schemas := get(t, doc, "components", "schemas")
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 100},
+			"email": {"type": "string", "format": "email"},
+			"password": {
+				"type": "string",
+				"format": "password",
+				"minLength": 8,
+				"maxLength": 72,
+				"description": "Must contain a lowercase letter, an uppercase letter and a number."
+			}
+		},
+		"required": ["name", "email", "password"]
+	}`, marshal(t, get(t, schemas, "RegisterInput")))
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"video_id": {"type": "string", "format": "uuid"},
+			"thumbnail_id": {"type": "string", "format": "uuid"},
+			"title": {"type": "string", "maxLength": 255},
+			"description": {"type": "string", "maxLength": 5000}
+		},
+		"required": ["video_id", "thumbnail_id", "title", "description"]
+	}`, marshal(t, get(t, schemas, "UploadedWebhookInput")))
+}
+
+func TestParameters(t *testing.T) {
+	params := openapi.Parameters("query", &types.FindAllVideosInput{})
+
+	byName := map[string]*openapi.Parameter{}
+	for _, p := range params {
+		assert.Equal(t, "query", p.In)
+		assert.False(t, p.Required)
+		byName[p.Name] = p
+	}
+
+	require.Len(t, byName, 7)
+	assert.Equal(t, 1.0, *byName["page"].Schema.Minimum)
+	assert.Equal(t, 100.0, *byName["limit"].Schema.Maximum)
+	assert.Equal(t, 512, *byName["cursor"].Schema.MaxLength)
+	assert.Equal(t, "Can not be combined with page.", byName["cursor"].Description)
+	assert.Equal(t, []any{"published_at", "duration"}, byName["sort_by"].Schema.Enum)
+	assert.Equal(t, "^[a-zA-Z0-9]+$", byName["resolution"].Schema.Pattern)
+}
+
+func TestGenerate(t *testing.T) {
+	doc := generate(t,
+		openapi.Route{
+			Method: http.MethodGet, Path: "/auth/profile", Service: "authentication", RPC: "Profile",
+			Auth: true, RateLimit: true, Backend: true,
+			Operation: openapi.Operation{
+				Summary:  "The authenticated user",
+				Response: openapi.Envelope(openapi.Object(map[string]*openapi.Schema{"user": openapi.Message(&authpb.User{})}, "user")),
+			},
+		},
+		openapi.Route{
+			Method: http.MethodPost, Path: "/auth/register", Service: "authentication", RPC: "Register", Backend: true,
+			Operation: openapi.Operation{
+				RequestBody: openapi.Struct(&types.RegisterInput{}),
+				Errors:      openapi.FieldErrors(&types.RegisterValidationError{}),
+				Response:    openapi.Message(&authpb.RegisterResponse{}),
+				Status:      http.StatusCreated,
+			},
+		},
+		openapi.Route{
+			Method: http.MethodGet, Path: "/metrics", Service: "gateway", RPC: "Metrics",
+			Operation: openapi.Operation{Response: openapi.String(), ContentType: "text/plain"},
+		},
+	)
+
+	assert.Equal(t, "3.1.0", doc["openapi"])
+	assert.Equal(t, []any{map[string]any{"name": "authentication"}, map[string]any{"name": "gateway"}}, doc["tags"])
+
+	profile := get(t, doc, "paths", "/auth/profile", "get")
+	assert.Equal(t, "authentication.Profile", get(t, profile, "operationId"))
+	assert.Equal(t, []any{map[string]any{"bearerAuth": []any{}}}, get(t, profile, "security"))
+	assert.ElementsMatch(t, []string{"200", "401", "429", "503", "default"}, keys(get(t, profile, "responses")))
+	assert.NotNil(t, get(t, profile, "responses", "429", "headers", "Retry-After"))
+	assert.Equal(t, "#/components/schemas/Problem", get(t, profile, "responses", "401", "content", "application/problem+json", "schema", "$ref"))
+
+	register := get(t, doc, "paths", "/auth/register", "post")
+	assert.Equal(t, "#/components/schemas/RegisterInput", get(t, register, "requestBody", "content", "application/json", "schema", "$ref"))
+	assert.Equal(t, "#/components/schemas/auth.RegisterResponse", get(t, register, "responses", "201", "content", "application/json", "schema", "$ref"))
+	assert.Equal(t, "#/components/schemas/RegisterValidationError",
+		get(t, register, "responses", "400", "content", "application/json", "schema", "properties", "data", "properties", "errors", "$ref"))
+
+	metrics := get(t, doc, "paths", "/metrics", "get")
+	assert.Equal(t, "string", get(t, metrics, "responses", "200", "content", "text/plain", "schema", "type"))
+	assert.ElementsMatch(t, []string{"200", "default"}, keys(get(t, metrics, "responses")))
+
+	// proto messages are added with the messages they refer to
+	schemas := get(t, doc, "components", "schemas")
+	for _, name := range []string{"auth.User", "auth.RegisterResponse", "auth.RegisterResponseData", "RegisterValidationError", "Error", "Problem"} {
+		assert.NotNil(t, get(t, schemas, name))
+	}
+	assert.Equal(t, "#/components/schemas/auth.RegisterResponseData", get(t, schemas, "auth.RegisterResponse", "properties", "data", "$ref"))
+	assert.Equal(t, map[string]any{"type": "string"}, get(t, schemas, "auth.User", "properties", "email"))
+}
+
+func TestRPC(t *testing.T) {
+	findById := openapi.RPC(method(t, &videocatalogpb.FindByIdRequest{}, "VideoCatalogService", "FindById"), http.MethodGet, "/videos/:id")
+	findAll := openapi.RPC(method(t, &videocatalogpb.FindAllRequest{}, "VideoCatalogService", "FindAll"), http.MethodGet, "/videos")
+	webhook := openapi.RPC(method(t, &uploadpb.UploadedWebhookRequest{}, "UploadService", "UploadedWebhook"), http.MethodPost, "/webhook")
+
+	doc := generate(t,
+		openapi.Route{Method: http.MethodGet, Path: "/videos/:id", Service: "video_catalog", RPC: "FindById", Operation: findById},
+		openapi.Route{Method: http.MethodGet, Path: "/videos", Service: "video_catalog", RPC: "FindAll", Operation: findAll},
+		openapi.Route{Method: http.MethodPost, Path: "/webhook", Service: "upload", RPC: "UploadedWebhook", Operation: webhook},
+	)
+
+	assert.JSONEq(t, `[
+		{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int32"}},
+		{"name": "Accept-Language", "in": "header", "description": "Locale of the gateway's messages, e.g. \"es\".", "schema": {"type": "string"}}
+	]`, marshal(t, get(t, doc, "paths", "/videos/{id}", "get", "parameters")))
+
+	var names []string
+	for _, p := range get(t, doc, "paths", "/videos", "get", "parameters").([]any) {
+		names = append(names, p.(map[string]any)["name"].(string))
+	}
+	assert.Equal(t, []string{"page", "limit", "cursor", "sort_by", "sort_order", "user_id", "resolution", "Accept-Language"}, names)
+
+	assert.Equal(t, "#/components/schemas/upload.UploadedWebhookRequest", get(t, doc, "paths", "/webhook", "post", "requestBody", "content", "application/json", "schema", "$ref"))
+	assert.Equal(t, "#/components/schemas/videocatalog.Video", get(t, doc, "components", "schemas", "videocatalog.FindAllResponseData", "properties", "videos", "items", "$ref"))
+}
+
+func TestBound(t *testing.T) {
+	findById := method(t, &videocatalogpb.FindByIdRequest{}, "VideoCatalogService", "FindById")
+	findAll := method(t, &videocatalogpb.FindAllRequest{}, "VideoCatalogService", "FindAll")
+	register := method(t, &authpb.RegisterRequest{}, "AuthenticationService", "Register")
+
+	tests := []struct {
+		name             string
+		op               openapi.Operation
+		expectParams     []string
+		expectBody       string
+		expectResponse   string
+		expectParamTypes map[string]string
+	}{
+		{
+			name:             "path params typed by the input message",
+			op:               openapi.Bound(findById, nil, http.MethodGet, "/videos/:id"),
+			expectParams:     []string{"path:id"},
+			expectResponse:   "#/components/schemas/videocatalog.FindByIdResponse",
+			expectParamTypes: map[string]string{"id": "integer"},
+		},
+		{
+			name:           "query params from the bound struct",
+			op:             openapi.Bound(findAll, &types.FindAllVideosInput{}, http.MethodGet, "/videos"),
+			expectParams:   []string{"query:page", "query:limit", "query:cursor", "query:sort_by", "query:sort_order", "query:user_id", "query:resolution"},
+			expectResponse: "#/components/schemas/videocatalog.FindAllResponse",
+		},
+		{
+			name:           "body from the bound struct",
+			op:             openapi.Bound(register, &types.RegisterInput{}, http.MethodPost, "/register"),
+			expectBody:     "#/components/schemas/RegisterInput",
+			expectResponse: "#/components/schemas/auth.RegisterResponse",
+		},
+		{
+			name:       "without rpc",
+			op:         openapi.Bound(nil, &types.LoginInput{}, http.MethodPost, "/login"),
+			expectBody: "#/components/schemas/LoginInput",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params []string
+			for _, p := range tt.op.Parameters {
+				params = append(params, p.In+":"+p.Name)
+				if typ, ok := tt.expectParamTypes[p.Name]; ok {
+					assert.Equal(t, typ, p.Schema.Type)
+				}
+			}
+			assert.Equal(t, tt.expectParams, params)
+
+			if tt.expectBody != "" {
+				require.NotNil(t, tt.op.RequestBody)
+				assert.Equal(t, tt.expectBody, tt.op.RequestBody.Ref)
+			} else {
+				assert.Nil(t, tt.op.RequestBody)
+			}
+
+			if tt.expectResponse != "" {
+				require.NotNil(t, tt.op.Response)
+				assert.Equal(t, tt.expectResponse, tt.op.Response.Ref)
+			} else {
+				assert.Nil(t, tt.op.Response)
+			}
+			assert.Empty(t, tt.op.Responses)
+		})
+	}
+}
+
+func TestDocsHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	openapi.DocsHandler("/openapi.json").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `data-spec-url="/openapi.json"`)
+	assert.NotContains(t, w.Body.String(), "https://")
+
+	csp := w.Header().Get("Content-Security-Policy")
+	assert.Contains(t, csp, "default-src 'none'")
+	for _, tag := range []string{"style", "script"} {
+		m := regexp.MustCompile(`(?s)<` + tag + `>(.*?)</` + tag + `>`).FindStringSubmatch(w.Body.String())
+		require.Len(t, m, 2, tag)
+
+		sum := sha256.Sum256([]byte(m[1]))
+		assert.Contains(t, csp, tag+"-src 'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'")
+	}
+}
+
+func marshal(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func keys(v any) []string {
+	var keys []string
+	for k := range v.(map[string]any) {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
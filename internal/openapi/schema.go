@@ -0,0 +1,285 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/validation"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func String() *Schema  { return &Schema{Type: "string"} }
+func Integer() *Schema { return &Schema{Type: "integer", Format: "int32"} }
+func Boolean() *Schema { return &Schema{Type: "boolean"} }
+
+func Array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// Envelope is the {message, data} body of helper.PrepareResponse.
+func Envelope(data *Schema) *Schema {
+	return Object(map[string]*Schema{"message": String(), "data": data}, "message")
+}
+
+func ref(component string, define func() *Schema) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + component, component: component, define: define}
+}
+
+// Struct describes the JSON body bound to v, a pointer to a struct. Fields are
+// named by their json tag and constrained by their binding tag.
+func Struct(v any) *Schema {
+	t := indirect(reflect.TypeOf(v))
+
+	return ref(t.Name(), func() *Schema {
+		s := Object(map[string]*Schema{})
+		for _, f := range reflect.VisibleFields(t) {
+			name := tagName(f, "json")
+			if name == "" {
+				continue
+			}
+
+			field, required := fieldSchema(t, f)
+			s.Properties[name] = field
+			if required {
+				s.Required = append(s.Required, name)
+			}
+		}
+
+		return s
+	})
+}
+
+// Parameters describes the query or header parameters bound to v, a pointer
+// to a struct, by their form or header tag.
+func Parameters(in string, v any) []*Parameter {
+	tag := map[string]string{"query": "form", "header": "header"}[in]
+	t := indirect(reflect.TypeOf(v))
+
+	var params []*Parameter
+	for _, f := range reflect.VisibleFields(t) {
+		name := tagName(f, tag)
+		if name == "" {
+			continue
+		}
+
+		schema, required := fieldSchema(t, f)
+		params = append(params, &Parameter{
+			Name:        name,
+			In:          in,
+			Required:    required,
+			Description: schema.Description,
+			Schema:      schema,
+		})
+		schema.Description = ""
+	}
+
+	return params
+}
+
+// FieldErrors describes the errors of a types.*ValidationError, every field
+// has a list of messages.
+func FieldErrors(v any) *Schema {
+	t := indirect(reflect.TypeOf(v))
+
+	return ref(t.Name(), func() *Schema {
+		s := Object(map[string]*Schema{})
+		for _, f := range reflect.VisibleFields(t) {
+			if name := tagName(f, "json"); name != "" {
+				s.Properties[name] = Array(String())
+				s.Required = append(s.Required, name)
+			}
+		}
+
+		return s
+	})
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func tagName(f reflect.StructField, tag string) string {
+	if !f.IsExported() {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+func typeSchema(t reflect.Type) *Schema {
+	t = indirect(t)
+
+	switch t.Kind() {
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return Integer()
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return Array(typeSchema(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: typeSchema(t.Elem())}
+	case reflect.Struct:
+		return Struct(reflect.New(t).Interface())
+	}
+
+	return &Schema{}
+}
+
+// fieldSchema describes field f of struct t with the constraints of its
+// binding tag, see validation for the custom rules.
+func fieldSchema(t reflect.Type, f reflect.StructField) (*Schema, bool) {
+	s := typeSchema(f.Type)
+	required := false
+
+	var notes []string
+	for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+		tag, param, _ := strings.Cut(rule, "=")
+
+		switch tag {
+		case "required":
+			required = true
+		case "email", "uuid":
+			s.Format = tag
+		case "min", "gte":
+			bound(s, param, &s.MinLength, &s.Minimum)
+		case "max", "lte":
+			bound(s, param, &s.MaxLength, &s.Maximum)
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, v)
+			}
+		case "alphanum":
+			s.Pattern = "^[a-zA-Z0-9]+$"
+		case "excluded_with":
+			var others []string
+			for _, name := range strings.Fields(param) {
+				if other, ok := t.FieldByName(name); ok {
+					others = append(others, firstTagName(other))
+				}
+			}
+			notes = append(notes, "Can not be combined with "+strings.Join(others, ", ")+".")
+		case "name":
+			s.MinLength, s.MaxLength = intPtr(validation.NameMinLength), intPtr(validation.NameMaxLength)
+		case "password":
+			s.MinLength, s.MaxLength = intPtr(validation.PasswordMinLength), intPtr(validation.PasswordMaxLength)
+			s.Format = "password"
+			notes = append(notes, "Must contain a lowercase letter, an uppercase letter and a number.")
+		}
+	}
+	s.Description = strings.Join(notes, " ")
+
+	return s, required
+}
+
+// bound sets a length for strings and a value for numbers.
+func bound(s *Schema, param string, length **int, value **float64) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	if s.Type == "string" {
+		*length = intPtr(int(n))
+	} else {
+		*value = &n
+	}
+}
+
+func firstTagName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "header"} {
+		if name := tagName(f, tag); name != "" {
+			return name
+		}
+	}
+
+	return f.Name
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// Message describes the JSON of a proto message. Fields are named like the
+// proto fields, as encoding/json and protojson with UseProtoNames do.
+func Message(m proto.Message) *Schema {
+	return messageSchema(m.ProtoReflect().Descriptor())
+}
+
+func messageSchema(md protoreflect.MessageDescriptor) *Schema {
+	return ref(string(md.FullName()), func() *Schema {
+		s := Object(map[string]*Schema{})
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			s.Properties[string(fd.Name())] = protoFieldSchema(fd)
+		}
+
+		return s
+	})
+}
+
+func protoFieldSchema(fd protoreflect.FieldDescriptor) *Schema {
+	if fd.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: protoKindSchema(fd.MapValue())}
+	}
+	if fd.IsList() {
+		return Array(protoKindSchema(fd))
+	}
+
+	return protoKindSchema(fd)
+}
+
+func protoKindSchema(fd protoreflect.FieldDescriptor) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return Boolean()
+	case protoreflect.StringKind:
+		return String()
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return Integer()
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.EnumKind:
+		s := String()
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			s.Enum = append(s.Enum, string(values.Get(i).Name()))
+		}
+		return s
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageSchema(fd.Message())
+	}
+
+	return &Schema{}
+}
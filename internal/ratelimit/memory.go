@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	expiresAt time.Time
+}
+
+type slidingWindow struct {
+	start     time.Time
+	current   int
+	previous  int
+	expiresAt time.Time
+}
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	windows   map[string]*slidingWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithClock(time.Now)
+}
+
+func NewMemoryStoreWithClock(now func() time.Time) *MemoryStore {
+	return &MemoryStore{
+		buckets:   map[string]*tokenBucket{},
+		windows:   map[string]*slidingWindow{},
+		lastSweep: now(),
+		now:       now,
+	}
+}
+
+func (m *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if limit.Algorithm == AlgorithmSlidingWindow {
+		return m.takeSlidingWindow(key, limit, now), nil
+	}
+
+	return m.takeTokenBucket(key, limit, now), nil
+}
+
+func (m *MemoryStore) takeTokenBucket(key string, limit Limit, now time.Time) Result {
+	capacity := float64(limit.Requests)
+	rate := capacity / limit.Window.Seconds() // tokens per second
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: capacity, updatedAt: now}
+		m.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	b.updatedAt = now
+	b.expiresAt = now.Add(limit.Window)
+
+	res := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+
+	res.Remaining = int(math.Floor(b.tokens))
+	res.ResetAfter = secondsToDuration((capacity - b.tokens) / rate)
+
+	return res
+}
+
+// takeSlidingWindow implements the sliding window counter: the previous
+// window's count is weighted by how much of it still overlaps the sliding window.
+func (m *MemoryStore) takeSlidingWindow(key string, limit Limit, now time.Time) Result {
+	w, ok := m.windows[key]
+	if !ok {
+		w = &slidingWindow{start: now.Truncate(limit.Window)}
+		m.windows[key] = w
+	}
+
+	start := now.Truncate(limit.Window)
+	switch elapsedWindows := int(start.Sub(w.start) / limit.Window); {
+	case elapsedWindows == 1:
+		w.previous, w.current = w.current, 0
+	case elapsedWindows > 1:
+		w.previous, w.current = 0, 0
+	}
+	w.start = start
+	w.expiresAt = start.Add(2 * limit.Window)
+
+	elapsed := now.Sub(start)
+	weight := 1 - elapsed.Seconds()/limit.Window.Seconds()
+	count := float64(w.previous)*weight + float64(w.current)
+
+	res := Result{Limit: limit.Requests, ResetAfter: limit.Window - elapsed}
+	if count < float64(limit.Requests) {
+		w.current++
+		count++
+		res.Allowed = true
+	} else {
+		res.RetryAfter = limit.Window - elapsed
+	}
+
+	res.Remaining = max(0, limit.Requests-int(math.Ceil(count)))
+
+	return res
+}
+
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if now.After(b.expiresAt) {
+			delete(m.buckets, key)
+		}
+	}
+	for key, w := range m.windows {
+		if now.After(w.expiresAt) {
+			delete(m.windows, key)
+		}
+	}
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,132 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	clock := newFakeClock()
+	store := ratelimit.NewMemoryStoreWithClock(clock.Now)
+	limit := ratelimit.Limit{Algorithm: ratelimit.AlgorithmTokenBucket, Requests: 3, Window: 3 * time.Second}
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take(ctx, "key", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, err := store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.ResetAfter)
+
+	// other keys have their own bucket
+	res, err = store.Take(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// one token is refilled per second
+	clock.Advance(time.Second)
+	res, err = store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	// the bucket never holds more than its capacity
+	clock.Advance(time.Hour)
+	res, err = store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+}
+
+func TestMemoryStore_SlidingWindow(t *testing.T) {
+	clock := newFakeClock()
+	store := ratelimit.NewMemoryStoreWithClock(clock.Now)
+	limit := ratelimit.Limit{Algorithm: ratelimit.AlgorithmSlidingWindow, Requests: 4, Window: 10 * time.Second}
+	ctx := context.Background()
+
+	for i := 3; i >= 0; i-- {
+		res, err := store.Take(ctx, "key", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, err := store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 10*time.Second, res.RetryAfter)
+
+	// halfway into the next window half of the previous window still counts
+	clock.Advance(15 * time.Second)
+	for range 2 {
+		res, err = store.Take(ctx, "key", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+	}
+
+	res, err = store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 5*time.Second, res.RetryAfter)
+
+	// after two full windows nothing counts anymore
+	clock.Advance(20 * time.Second)
+	res, err = store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 3, res.Remaining)
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  ratelimit.Algorithm
+		expectErr bool
+	}{
+		{"token_bucket", ratelimit.AlgorithmTokenBucket, false},
+		{"sliding_window", ratelimit.AlgorithmSlidingWindow, false},
+		{"leaky_bucket", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ratelimit.ParseAlgorithm(tt.input)
+		if tt.expectErr {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, tt.expected, got)
+	}
+}
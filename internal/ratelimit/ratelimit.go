@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmTokenBucket   Algorithm = "token_bucket"
+	AlgorithmSlidingWindow Algorithm = "sliding_window"
+)
+
+// Limit allows Requests per Window for a single key.
+type Limit struct {
+	Algorithm Algorithm
+	Requests  int
+	Window    time.Duration
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps the limiter state for every key. Implementations must be safe
+// for concurrent use and apply Take atomically per key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch Algorithm(s) {
+	case AlgorithmTokenBucket, AlgorithmSlidingWindow:
+		return Algorithm(s), nil
+	default:
+		return "", fmt.Errorf("unknown rate limit algorithm %q", s)
+	}
+}
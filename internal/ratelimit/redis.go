@@ -0,0 +1,178 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisScripter is the subset of a Redis client needed by RedisStore, any
+// client that can run EVAL can be adapted to it.
+type RedisScripter interface {
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// GoRedisScripter adapts a go-redis client, e.g. *redis.Client or
+// *redis.ClusterClient, to RedisScripter.
+type GoRedisScripter struct {
+	Client redis.Scripter
+}
+
+func (s GoRedisScripter) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	return s.Client.Eval(ctx, script, keys, args...).Result()
+}
+
+const tokenBucketScript = `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) / 1000 * rate)
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], ttl)
+
+return {allowed, tostring(tokens)}
+`
+
+const slidingWindowScript = `
+local limit = tonumber(ARGV[1])
+local weight = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+local previous = tonumber(redis.call("GET", KEYS[2]) or "0")
+local count = previous * weight + current
+
+local allowed = 0
+if count < limit then
+  redis.call("INCR", KEYS[1])
+  redis.call("PEXPIRE", KEYS[1], ttl)
+  count = count + 1
+  allowed = 1
+end
+
+return {allowed, tostring(count)}
+`
+
+type RedisStore struct {
+	client RedisScripter
+	prefix string
+	now    func() time.Time
+}
+
+func NewRedisStore(client RedisScripter, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix, now: time.Now}
+}
+
+func (r *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.Algorithm == AlgorithmSlidingWindow {
+		return r.takeSlidingWindow(ctx, key, limit)
+	}
+
+	return r.takeTokenBucket(ctx, key, limit)
+}
+
+func (r *RedisStore) takeTokenBucket(ctx context.Context, key string, limit Limit) (Result, error) {
+	capacity := float64(limit.Requests)
+	rate := capacity / limit.Window.Seconds()
+
+	raw, err := r.client.Eval(ctx, tokenBucketScript,
+		[]string{fmt.Sprintf("%s{%s}:tb", r.prefix, key)},
+		limit.Requests, strconv.FormatFloat(rate, 'f', -1, 64), r.now().UnixMilli(), limit.Window.Milliseconds(),
+	)
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, tokens, err := parseScriptResult(raw)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsToDuration((capacity - tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+
+	return res, nil
+}
+
+func (r *RedisStore) takeSlidingWindow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := r.now()
+	start := now.Truncate(limit.Window)
+	elapsed := now.Sub(start)
+	weight := 1 - elapsed.Seconds()/limit.Window.Seconds()
+
+	raw, err := r.client.Eval(ctx, slidingWindowScript,
+		[]string{
+			fmt.Sprintf("%s{%s}:sw:%d", r.prefix, key, start.UnixMilli()),
+			fmt.Sprintf("%s{%s}:sw:%d", r.prefix, key, start.Add(-limit.Window).UnixMilli()),
+		},
+		limit.Requests, strconv.FormatFloat(weight, 'f', -1, 64), (2 * limit.Window).Milliseconds(),
+	)
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, count, err := parseScriptResult(raw)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		Remaining:  max(0, limit.Requests-int(math.Ceil(count))),
+		ResetAfter: limit.Window - elapsed,
+	}
+	if !allowed {
+		res.RetryAfter = limit.Window - elapsed
+	}
+
+	return res, nil
+}
+
+func parseScriptResult(raw any) (bool, float64, error) {
+	values, ok := raw.([]any)
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result %v", raw)
+	}
+
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected rate limit script result %v", raw)
+	}
+
+	s, ok := values[1].(string)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected rate limit script result %v", raw)
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("unexpected rate limit script result %v: %w", raw, err)
+	}
+
+	return allowed == 1, n, nil
+}
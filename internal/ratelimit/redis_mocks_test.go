@@ -0,0 +1,17 @@
+package ratelimit_test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRedisScripter struct {
+	mock.Mock
+}
+
+func (m *MockRedisScripter) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	ret := m.Called(ctx, script, keys, args)
+
+	return ret.Get(0), ret.Error(1)
+}
@@ -0,0 +1,122 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore_Take(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         ratelimit.Limit
+		keysMatcher   func(keys []string) bool
+		scriptResult  any
+		scriptErr     error
+		expectErr     bool
+		expectAllowed bool
+		expectRemain  int
+	}{
+		{
+			name:  "token bucket allowed",
+			limit: ratelimit.Limit{Algorithm: ratelimit.AlgorithmTokenBucket, Requests: 10, Window: 10 * time.Second},
+			keysMatcher: func(keys []string) bool {
+				return len(keys) == 1 && keys[0] == "rl:{ip:1.1.1.1}:tb"
+			},
+			scriptResult:  []any{int64(1), "4.5"},
+			expectAllowed: true,
+			expectRemain:  4,
+		},
+		{
+			name:  "sliding window denied",
+			limit: ratelimit.Limit{Algorithm: ratelimit.AlgorithmSlidingWindow, Requests: 10, Window: time.Minute},
+			keysMatcher: func(keys []string) bool {
+				return len(keys) == 2
+			},
+			scriptResult:  []any{int64(0), "10"},
+			expectAllowed: false,
+			expectRemain:  0,
+		},
+		{
+			name:        "script error",
+			limit:       ratelimit.Limit{Algorithm: ratelimit.AlgorithmTokenBucket, Requests: 10, Window: time.Minute},
+			keysMatcher: func(keys []string) bool { return true },
+			scriptErr:   errors.New("connection refused"),
+			expectErr:   true,
+		},
+		{
+			name:         "unexpected script result",
+			limit:        ratelimit.Limit{Algorithm: ratelimit.AlgorithmTokenBucket, Requests: 10, Window: time.Minute},
+			keysMatcher:  func(keys []string) bool { return true },
+			scriptResult: "OK",
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(MockRedisScripter)
+			client.On("Eval", mock.Anything, mock.Anything, mock.MatchedBy(tt.keysMatcher), mock.Anything).
+				Return(tt.scriptResult, tt.scriptErr).
+				Once()
+
+			store := ratelimit.NewRedisStore(client, "rl:")
+
+			res, err := store.Take(context.Background(), "ip:1.1.1.1", tt.limit)
+
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectAllowed, res.Allowed)
+				assert.Equal(t, tt.expectRemain, res.Remaining)
+				assert.Equal(t, tt.limit.Requests, res.Limit)
+				if !tt.expectAllowed {
+					assert.Positive(t, res.RetryAfter)
+				}
+			}
+
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisStore_GoRedis(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm ratelimit.Algorithm
+	}{
+		{name: "token bucket", algorithm: ratelimit.AlgorithmTokenBucket},
+		{name: "sliding window", algorithm: ratelimit.AlgorithmSlidingWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			limit := ratelimit.Limit{Algorithm: tt.algorithm, Requests: 2, Window: time.Minute}
+
+			// two replicas of the gateway share the limit through the server
+			replicas := make([]*ratelimit.RedisStore, 2)
+			for i := range replicas {
+				client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+				t.Cleanup(func() { client.Close() })
+
+				replicas[i] = ratelimit.NewRedisStore(ratelimit.GoRedisScripter{Client: client}, "rl:")
+			}
+
+			for i, expectAllowed := range []bool{true, true, false} {
+				res, err := replicas[i%2].Take(context.Background(), "ip:1.1.1.1", limit)
+				require.NoError(t, err)
+				assert.Equal(t, expectAllowed, res.Allowed, "request %d", i+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// maxLength caps request ids taken from clients so they can't blow up log
+// lines and metadata.
+const maxLength = 128
+
+type contextKey struct{}
+
+func New() string {
+	return uuid.NewString()
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id of ctx, or "" outside of a request.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Valid reports whether an id sent by a client can be reused, only printable
+// ASCII without spaces is accepted.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,91 @@
+package responsecache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/cache"
+)
+
+// MemoryStore keeps responses in a size bounded LRU. Invalidation bumps a
+// generation per tag instead of tracking keys, entries stored under an older
+// generation are treated as misses and aged out by the LRU.
+type MemoryStore struct {
+	entries *cache.LRU[string, memoryEntry]
+
+	mu          sync.Mutex
+	generations map[string]uint64
+}
+
+type memoryEntry struct {
+	entry       *Entry
+	generations Versions
+}
+
+func NewMemoryStore(size int) *MemoryStore {
+	return &MemoryStore{
+		entries:     cache.NewLRU[string, memoryEntry](size, 0),
+		generations: map[string]uint64{},
+	}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	e, ok := s.entries.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, tag := range e.entry.Tags {
+		if s.generations[tag] != e.generations[i] {
+			s.entries.Delete(key)
+			return nil, false, nil
+		}
+	}
+
+	return e.entry, true, nil
+}
+
+func (s *MemoryStore) Versions(ctx context.Context, tags []string) (Versions, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := make(Versions, len(tags))
+	for i, tag := range tags {
+		versions[i] = s.generations[tag]
+	}
+
+	return versions, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, entry *Entry, ttl time.Duration, versions Versions) error {
+	if len(versions) != len(entry.Tags) {
+		return fmt.Errorf("%d versions for %d tags", len(versions), len(entry.Tags))
+	}
+
+	// the lock is held while storing so an Invalidate can't slip in between
+	// the check and the store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, tag := range entry.Tags {
+		if s.generations[tag] != versions[i] {
+			return nil
+		}
+	}
+
+	s.entries.SetWithTTL(key, memoryEntry{entry: entry, generations: versions}, ttl)
+	return nil
+}
+
+func (s *MemoryStore) Invalidate(ctx context.Context, tag string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generations[tag]++
+	return nil
+}
@@ -0,0 +1,75 @@
+package responsecache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/responsecache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func set(t *testing.T, store *responsecache.MemoryStore, key string, entry *responsecache.Entry) {
+	t.Helper()
+
+	versions, err := store.Versions(context.Background(), entry.Tags)
+	require.NoError(t, err)
+	require.NoError(t, store.Set(context.Background(), key, entry, time.Minute, versions))
+}
+
+func TestMemoryStore_GetSet(t *testing.T) {
+	store := responsecache.NewMemoryStore(10)
+	ctx := context.Background()
+
+	_, ok, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	entry := &responsecache.Entry{Status: 200, Body: []byte("body")}
+	set(t, store, "key", entry)
+
+	got, ok, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, entry, got)
+}
+
+func TestMemoryStore_Invalidate(t *testing.T) {
+	store := responsecache.NewMemoryStore(10)
+	ctx := context.Background()
+
+	set(t, store, "list", &responsecache.Entry{Tags: []string{"videos"}})
+	set(t, store, "detail", &responsecache.Entry{})
+
+	require.NoError(t, store.Invalidate(ctx, "videos"))
+
+	_, ok, _ := store.Get(ctx, "list")
+	assert.False(t, ok, "tagged entry should be invalidated")
+
+	_, ok, _ = store.Get(ctx, "detail")
+	assert.True(t, ok, "untagged entry should be kept")
+
+	// entries stored after the invalidation are valid again
+	set(t, store, "list", &responsecache.Entry{Tags: []string{"videos"}})
+	_, ok, _ = store.Get(ctx, "list")
+	assert.True(t, ok)
+}
+
+func TestMemoryStore_SetAfterInvalidate(t *testing.T) {
+	store := responsecache.NewMemoryStore(10)
+	ctx := context.Background()
+	entry := &responsecache.Entry{Tags: []string{"other", "videos"}}
+
+	versions, err := store.Versions(ctx, entry.Tags)
+	require.NoError(t, err)
+
+	// the response was produced before the invalidation, it may be stale
+	require.NoError(t, store.Invalidate(ctx, "videos"))
+	require.NoError(t, store.Set(ctx, "list", entry, time.Minute, versions))
+
+	_, ok, _ := store.Get(ctx, "list")
+	assert.False(t, ok)
+
+	assert.Error(t, store.Set(ctx, "list", entry, time.Minute, nil), "versions must match the tags")
+}
@@ -0,0 +1,35 @@
+package responsecache
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Entry is a cached response.
+type Entry struct {
+	Status       int
+	Header       http.Header
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+	// Tags group entries so they can be invalidated together.
+	Tags []string
+}
+
+// Versions is the invalidation state of a list of tags, each Invalidate of a
+// tag moves it on.
+type Versions []uint64
+
+type Store interface {
+	// Get returns the entry stored under key, ok is false on a miss.
+	Get(ctx context.Context, key string) (entry *Entry, ok bool, err error)
+	// Versions snapshots the state of tags before the response is produced.
+	Versions(ctx context.Context, tags []string) (Versions, error)
+	// Set stores entry, produced as of versions of its tags. It is dropped
+	// when one of them has been invalidated since, the entry may predate the
+	// change the invalidation announced.
+	Set(ctx context.Context, key string, entry *Entry, ttl time.Duration, versions Versions) error
+	// Invalidate drops every entry tagged with tag.
+	Invalidate(ctx context.Context, tag string) error
+}
@@ -0,0 +1,167 @@
+package route
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultTable is the route table used when no ROUTES_FILE is configured.
+//
+//go:embed routes.yaml
+var defaultTable []byte
+
+var methods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+type Table struct {
+	Routes []Route `yaml:"routes"`
+}
+
+// Route maps an HTTP method and path to the RPC of a backend service.
+type Route struct {
+	Method  string `yaml:"method"`
+	Path    string `yaml:"path"`
+	Service string `yaml:"service"`
+	RPC     string `yaml:"rpc"`
+	// Auth requires a valid bearer token.
+	Auth bool `yaml:"auth"`
+	// Timeout bounds the request context, 0 keeps the client timeouts.
+	Timeout time.Duration `yaml:"timeout"`
+	// RateLimits names the RATE_LIMIT_* rules applied to the route.
+	RateLimits []string `yaml:"rate_limits"`
+	Cache      *Cache   `yaml:"cache"`
+	// Invalidates names the cache tags dropped once the route succeeds.
+	Invalidates []string `yaml:"invalidates"`
+}
+
+// Cache sets Cache-Control for ttl, responses of public routes are also
+// cached by the gateway under tags.
+type Cache struct {
+	TTL  time.Duration `yaml:"ttl"`
+	Tags []string      `yaml:"tags"`
+}
+
+// Binding is the key handlers are registered under, e.g. "upload.CreatePresignedUrl".
+func (r Route) Binding() string {
+	return r.Service + "." + r.RPC
+}
+
+func (r Route) String() string {
+	return r.Method + " " + r.Path
+}
+
+func Default() (*Table, error) {
+	return Parse(defaultTable)
+}
+
+func Load(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read route table: %w", err)
+	}
+
+	table, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return table, nil
+}
+
+// Parse decodes and validates a route table. JSON is accepted as well since
+// it is a subset of YAML.
+func Parse(data []byte) (*Table, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	table := &Table{}
+	if err := dec.Decode(table); err != nil {
+		return nil, fmt.Errorf("invalid route table: %w", err)
+	}
+
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// Validate reports every invalid route at once so a broken table can be fixed
+// in one go.
+func (t *Table) Validate() error {
+	if len(t.Routes) == 0 {
+		return errors.New("invalid route table: no routes defined")
+	}
+
+	var errs []error
+	seen := map[string]int{}
+
+	for i, r := range t.Routes {
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("routes[%d] %s: %s", i, r, fmt.Sprintf(format, args...)))
+		}
+
+		if !slices.Contains(methods, r.Method) {
+			fail("unsupported method %q", r.Method)
+		}
+		if !strings.HasPrefix(r.Path, "/") {
+			fail("path must start with \"/\"")
+		}
+		if r.Service == "" {
+			fail("service is required")
+		}
+		if r.RPC == "" {
+			fail("rpc is required")
+		}
+		if r.Timeout < 0 {
+			fail("timeout must not be negative")
+		}
+		for _, name := range r.RateLimits {
+			if name == "" {
+				fail("rate limit name must not be empty")
+			}
+		}
+		if r.Cache != nil {
+			if r.Cache.TTL <= 0 {
+				fail("cache ttl must be positive")
+			}
+			if r.Method != http.MethodGet {
+				fail("only GET routes can be cached")
+			}
+			for _, tag := range r.Cache.Tags {
+				if tag == "" {
+					fail("cache tag must not be empty")
+				}
+			}
+		}
+		for _, tag := range r.Invalidates {
+			if tag == "" {
+				fail("invalidated cache tag must not be empty")
+			}
+		}
+
+		if j, ok := seen[r.String()]; ok {
+			fail("duplicate of routes[%d]", j)
+		} else {
+			seen[r.String()] = i
+		}
+	}
+
+	return errors.Join(errs...)
+}
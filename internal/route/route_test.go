@@ -0,0 +1,131 @@
+package route_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/route"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	table, err := route.Default()
+	require.NoError(t, err)
+	assert.NotEmpty(t, table.Routes)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expect    route.Route
+		expectErr []string
+	}{
+		{
+			name: "yaml",
+			data: `
+routes:
+  - method: GET
+    path: /videos
+    service: video_catalog
+    rpc: FindAll
+    timeout: 2s
+    rate_limits: [videos]
+    cache:
+      ttl: 30s
+`,
+			expect: route.Route{
+				Method:     "GET",
+				Path:       "/videos",
+				Service:    "video_catalog",
+				RPC:        "FindAll",
+				Timeout:    2 * time.Second,
+				RateLimits: []string{"videos"},
+				Cache:      &route.Cache{TTL: 30 * time.Second},
+			},
+		},
+		{
+			name: "json",
+			data: `{"routes": [{"method": "POST", "path": "/auth/logout", "service": "authentication", "rpc": "Logout", "auth": true}]}`,
+			expect: route.Route{
+				Method:  "POST",
+				Path:    "/auth/logout",
+				Service: "authentication",
+				RPC:     "Logout",
+				Auth:    true,
+			},
+		},
+		{
+			name:      "empty",
+			data:      `routes: []`,
+			expectErr: []string{"no routes defined"},
+		},
+		{
+			name:      "unknown field",
+			data:      "routes:\n  - method: GET\n    path: /x\n    servce: upload\n",
+			expectErr: []string{"field servce not found"},
+		},
+		{
+			name: "every invalid route is reported",
+			data: `
+routes:
+  - method: FETCH
+    path: videos
+  - method: POST
+    path: /videos
+    service: video_catalog
+    rpc: FindAll
+    timeout: -1s
+    cache:
+      ttl: 0s
+  - method: POST
+    path: /videos
+    service: video_catalog
+    rpc: FindAll
+`,
+			expectErr: []string{
+				`routes[0] FETCH videos: unsupported method "FETCH"`,
+				`routes[0] FETCH videos: path must start with "/"`,
+				`routes[0] FETCH videos: service is required`,
+				`routes[0] FETCH videos: rpc is required`,
+				`routes[1] POST /videos: timeout must not be negative`,
+				`routes[1] POST /videos: cache ttl must be positive`,
+				`routes[1] POST /videos: only GET routes can be cached`,
+				`routes[2] POST /videos: duplicate of routes[1]`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := route.Parse([]byte(tt.data))
+
+			if len(tt.expectErr) > 0 {
+				require.Error(t, err)
+				for _, msg := range tt.expectErr {
+					assert.Contains(t, err.Error(), msg)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, table.Routes, 1)
+			assert.Equal(t, tt.expect, table.Routes[0])
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("routes:\n  - method: GET\n    path: /health\n    service: gateway\n    rpc: Health\n"), 0644))
+
+	table, err := route.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "gateway.Health", table.Routes[0].Binding())
+
+	_, err = route.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read route table")
+}
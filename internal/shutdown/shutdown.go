@@ -0,0 +1,78 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+)
+
+type HookFunc func(ctx context.Context) error
+
+type hook struct {
+	name    string
+	timeout time.Duration
+	fn      HookFunc
+}
+
+// Coordinator runs the shutdown hooks one after another in the order they
+// were added, so e.g. the HTTP server stops before the connections it uses
+// are closed.
+type Coordinator struct {
+	hooks []hook
+}
+
+func New() *Coordinator {
+	return &Coordinator{}
+}
+
+// Add appends a hook, a timeout above zero bounds the context fn gets.
+func (c *Coordinator) Add(name string, timeout time.Duration, fn HookFunc) {
+	c.hooks = append(c.hooks, hook{name: name, timeout: timeout, fn: fn})
+}
+
+// Shutdown runs every hook, a failing hook doesn't stop the ones after it.
+func (c *Coordinator) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	for _, h := range c.hooks {
+		start := time.Now()
+		if err := c.run(ctx, h); err != nil {
+			logger.Warn().Str("hook", h.name).Err(err).Msg("Shutdown hook failed")
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+
+		logger.Info().Str("hook", h.name).Dur("latency", time.Since(start)).Msg("Shutdown hook done")
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Coordinator) run(ctx context.Context, h hook) error {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	return h.fn(ctx)
+}
+
+// Wait returns a hook that waits for d, e.g. for load balancers to notice the
+// gateway is no longer ready before it stops accepting connections.
+func Wait(d time.Duration) HookFunc {
+	return func(ctx context.Context) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package shutdown_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/shutdown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinator_Shutdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		hooks         map[string]error
+		expectedOrder []string
+		expectedErr   []string
+	}{
+		{
+			name:          "runs hooks in order",
+			hooks:         map[string]error{},
+			expectedOrder: []string{"readiness", "drain", "http server", "tracer"},
+		},
+		{
+			name:          "failing hook does not stop later hooks",
+			hooks:         map[string]error{"http server": errors.New("deadline exceeded"), "tracer": errors.New("exporter down")},
+			expectedOrder: []string{"readiness", "drain", "http server", "tracer"},
+			expectedErr:   []string{"http server: deadline exceeded", "tracer: exporter down"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order []string
+			c := shutdown.New()
+			for _, name := range []string{"readiness", "drain", "http server", "tracer"} {
+				c.Add(name, 0, func(ctx context.Context) error {
+					order = append(order, name)
+					return tt.hooks[name]
+				})
+			}
+
+			err := c.Shutdown(context.Background())
+
+			assert.Equal(t, tt.expectedOrder, order)
+			if len(tt.expectedErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, msg := range tt.expectedErr {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestCoordinator_Shutdown_HookTimeout(t *testing.T) {
+	c := shutdown.New()
+	c.Add("slow", 20*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	err := c.Shutdown(context.Background())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWait(t *testing.T) {
+	start := time.Now()
+	require.NoError(t, shutdown.Wait(20*time.Millisecond)(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, shutdown.Wait(time.Hour)(ctx), context.Canceled)
+}
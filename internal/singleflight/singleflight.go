@@ -0,0 +1,227 @@
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrPanic is returned to every caller of a call that panicked.
+var ErrPanic = errors.New("singleflight: call panicked")
+
+// Group deduplicates concurrent calls with the same key: the first caller
+// starts the call and every caller arriving while it is in flight waits for
+// and shares its result.
+//
+// The call runs on a context that is detached from the callers'
+// cancellation, so one caller giving up does not fail the call for the
+// others. It is cancelled once every caller has given up, and its deadline is
+// the latest one of its callers. By default it keeps the values of the caller
+// that started it, e.g. its request id and trace span, set Detach and Join to
+// give the call its own.
+type Group[K comparable, V any] struct {
+	// Detach returns the context whose values the call runs with from the
+	// context of the caller starting it, its cancellation is ignored. Set it
+	// before the first Do.
+	Detach func(ctx context.Context) context.Context
+	// Join is called with the context of the call for every caller that
+	// starts or joins it, e.g. to link their traces. Set it before the first
+	// Do.
+	Join func(call context.Context, caller context.Context)
+
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+type call[V any] struct {
+	done    chan struct{}
+	ctx     *callContext
+	waiters int
+	val     V
+	err     error
+}
+
+func NewGroup[K comparable, V any]() *Group[K, V] {
+	return &Group[K, V]{calls: map[K]*call[V]{}}
+}
+
+// Do runs fn once for all concurrent callers of key. shared reports whether
+// the result was produced by a call started by another caller.
+func (g *Group[K, V]) Do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (v V, shared bool, err error) {
+	g.mu.Lock()
+	c, ok := g.calls[key]
+	if ok {
+		c.waiters++
+		c.ctx.extend(ctx)
+	} else {
+		values := context.WithoutCancel(ctx)
+		if g.Detach != nil {
+			values = g.Detach(ctx)
+		}
+		c = &call[V]{done: make(chan struct{}), ctx: newCallContext(values, ctx), waiters: 1}
+		g.calls[key] = c
+
+		go g.run(key, c, fn)
+	}
+	g.mu.Unlock()
+
+	if g.Join != nil {
+		g.Join(c.ctx, ctx)
+	}
+
+	select {
+	case <-c.done:
+		return c.val, ok, c.err
+	case <-ctx.Done():
+		g.leave(key, c)
+
+		var zero V
+		return zero, ok, ctx.Err()
+	}
+}
+
+func (g *Group[K, V]) run(key K, c *call[V], fn func(ctx context.Context) (V, error)) {
+	defer func() {
+		// a panic would take the process down from this goroutine, the
+		// callers get it as an error instead
+		if r := recover(); r != nil {
+			var zero V
+			c.val, c.err = zero, fmt.Errorf("%w: %v", ErrPanic, r)
+		}
+
+		c.ctx.cancel(context.Canceled)
+
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	c.val, c.err = fn(c.ctx)
+}
+
+// leave drops a caller that gave up, the last one to leave cancels the call
+// and forgets it so later callers start a fresh one.
+func (g *Group[K, V]) leave(key K, c *call[V]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c.waiters--
+	if c.waiters > 0 {
+		return
+	}
+
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	c.ctx.cancel(context.Canceled)
+}
+
+// callContext carries the values of the call, see Group.Detach. It expires
+// at the latest deadline of the callers, or never once a caller without a
+// deadline joined.
+type callContext struct {
+	context.Context
+
+	done chan struct{}
+
+	mu        sync.Mutex
+	err       error
+	deadline  time.Time
+	unbounded bool
+	timer     *time.Timer
+}
+
+func newCallContext(values context.Context, ctx context.Context) *callContext {
+	c := &callContext{Context: values, done: make(chan struct{})}
+	c.extend(ctx)
+
+	return c
+}
+
+func (c *callContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.unbounded {
+		return time.Time{}, false
+	}
+	return c.deadline, true
+}
+
+func (c *callContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *callContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// extend moves the deadline to the one of ctx when it is later.
+func (c *callContext) extend(ctx context.Context) {
+	deadline, ok := ctx.Deadline()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil || c.unbounded {
+		return
+	}
+
+	if !ok {
+		c.unbounded = true
+		if c.timer != nil {
+			c.timer.Stop()
+		}
+		return
+	}
+
+	if !deadline.After(c.deadline) {
+		return
+	}
+	c.deadline = deadline
+
+	if c.timer == nil {
+		c.timer = time.AfterFunc(time.Until(deadline), c.expire)
+	} else {
+		c.timer.Reset(time.Until(deadline))
+	}
+}
+
+func (c *callContext) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the deadline may have been extended while the timer fired
+	if c.unbounded || time.Now().Before(c.deadline) {
+		return
+	}
+	c.cancelLocked(context.DeadlineExceeded)
+}
+
+func (c *callContext) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cancelLocked(err)
+}
+
+func (c *callContext) cancelLocked(err error) {
+	if c.err != nil {
+		return
+	}
+
+	c.err = err
+	close(c.done)
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+}
@@ -0,0 +1,310 @@
+package singleflight_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/singleflight"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startCallers calls Do for key from n goroutines and returns once all of them
+// got a result.
+func startCallers(t *testing.T, g *singleflight.Group[string, int], n int, ctx func(i int) context.Context, fn func(ctx context.Context) (int, error)) ([]int, []bool, []error) {
+	t.Helper()
+
+	vals := make([]int, n)
+	shared := make([]bool, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vals[i], shared[i], errs[i] = g.Do(ctx(i), "key", fn)
+		}()
+	}
+	wg.Wait()
+
+	return vals, shared, errs
+}
+
+func TestGroup_Do_SharesInFlightCall(t *testing.T) {
+	g := singleflight.NewGroup[string, int]()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+
+	vals, shared, errs := startCallers(t, g, 5, func(int) context.Context { return context.Background() }, fn)
+
+	assert.EqualValues(t, 1, calls.Load())
+	sharedCount := 0
+	for i := range vals {
+		require.NoError(t, errs[i])
+		assert.Equal(t, 42, vals[i])
+		if shared[i] {
+			sharedCount++
+		}
+	}
+	assert.Equal(t, 4, sharedCount)
+}
+
+func TestGroup_Do_SharesErrors(t *testing.T) {
+	g := singleflight.NewGroup[string, int]()
+
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		<-release
+		return 0, errors.New("unavailable")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+
+	_, _, errs := startCallers(t, g, 3, func(int) context.Context { return context.Background() }, fn)
+
+	for _, err := range errs {
+		assert.EqualError(t, err, "unavailable")
+	}
+}
+
+func TestGroup_Do_CancelledCallerDoesNotCancelOthers(t *testing.T) {
+	g := singleflight.NewGroup[string, int]()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var callErr error
+	fn := func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		callErr = ctx.Err()
+		return 42, nil
+	}
+
+	first := make(chan error)
+	go func() {
+		_, _, err := g.Do(cancelled, "key", fn)
+		first <- err
+	}()
+	<-started
+
+	second := make(chan int)
+	go func() {
+		v, shared, err := g.Do(context.Background(), "key", fn)
+		assert.True(t, shared)
+		assert.NoError(t, err)
+		second <- v
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	close(release)
+	assert.Equal(t, 42, <-second)
+	assert.NoError(t, callErr, "shared call must not see the first caller's cancellation")
+}
+
+func TestGroup_Do_LastCallerLeavingCancelsCall(t *testing.T) {
+	g := singleflight.NewGroup[string, int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	callCancelled := make(chan struct{})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	_, _, err := g.Do(ctx, "key", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(callCancelled)
+		return 0, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	select {
+	case <-callCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("call was not cancelled after every caller left")
+	}
+
+	v, shared, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return 7, nil
+	})
+	require.NoError(t, err)
+	assert.False(t, shared, "a later caller must start a fresh call")
+	assert.Equal(t, 7, v)
+}
+
+func TestGroup_Do_KeepsContextValues(t *testing.T) {
+	type ctxKey struct{}
+
+	g := singleflight.NewGroup[string, string]()
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	v, _, err := g.Do(ctx, "key", func(ctx context.Context) (string, error) {
+		return ctx.Value(ctxKey{}).(string), nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "value", v)
+}
+
+func TestGroup_Do_DetachAndJoin(t *testing.T) {
+	type ctxKey struct{}
+
+	g := singleflight.NewGroup[string, int]()
+	var detached atomic.Int32
+	g.Detach = func(ctx context.Context) context.Context {
+		detached.Add(1)
+		return context.WithValue(context.Background(), ctxKey{}, "call")
+	}
+
+	var mu sync.Mutex
+	joined := map[int]string{}
+	g.Join = func(call context.Context, caller context.Context) {
+		mu.Lock()
+		defer mu.Unlock()
+		joined[caller.Value(ctxKey{}).(int)] = call.Value(ctxKey{}).(string)
+	}
+
+	release := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+
+	var values []any
+	_, _, errs := startCallers(t, g, 3, func(i int) context.Context {
+		return context.WithValue(context.Background(), ctxKey{}, i)
+	}, func(ctx context.Context) (int, error) {
+		values = append(values, ctx.Value(ctxKey{}))
+		<-release
+		return 0, nil
+	})
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []any{"call"}, values, "the call runs with the values of Detach")
+	assert.EqualValues(t, 1, detached.Load())
+	assert.Equal(t, map[int]string{0: "call", 1: "call", 2: "call"}, joined, "every caller joins the call")
+}
+
+func TestGroup_Do_PanicIsReturnedToEveryCaller(t *testing.T) {
+	g := singleflight.NewGroup[string, int]()
+
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		<-release
+		panic("boom")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+
+	_, _, errs := startCallers(t, g, 3, func(int) context.Context { return context.Background() }, fn)
+
+	for _, err := range errs {
+		assert.ErrorIs(t, err, singleflight.ErrPanic)
+		assert.ErrorContains(t, err, "boom")
+	}
+
+	// the key is free again
+	v, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 1, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestGroup_Do_LatestDeadline(t *testing.T) {
+	g := singleflight.NewGroup[string, int]()
+
+	first, cancelFirst := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelFirst()
+	second, cancelSecond := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelSecond()
+
+	deadlines := make(chan time.Time, 2)
+	started := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		d, ok := ctx.Deadline()
+		assert.True(t, ok)
+		deadlines <- d
+		close(started)
+
+		// the second caller joins with a later deadline
+		time.Sleep(100 * time.Millisecond)
+		d, _ = ctx.Deadline()
+		deadlines <- d
+		return 1, ctx.Err()
+	}
+
+	go func() { _, _, _ = g.Do(first, "key", fn) }()
+	<-started
+	v, shared, err := g.Do(second, "key", fn)
+
+	require.NoError(t, err, "the call outlives the deadline of the first caller")
+	assert.True(t, shared)
+	assert.Equal(t, 1, v)
+
+	firstDeadline, _ := first.Deadline()
+	secondDeadline, _ := second.Deadline()
+	assert.Equal(t, firstDeadline, <-deadlines)
+	assert.Equal(t, secondDeadline, <-deadlines)
+}
+
+func TestGroup_Do_DeadlineExpiresDetachedCall(t *testing.T) {
+	g := singleflight.NewGroup[string, int]()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	callErr := make(chan error, 1)
+	_, _, err := g.Do(ctx, "key", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		callErr <- ctx.Err()
+		return 0, ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	select {
+	case err := <-callErr:
+		assert.True(t, errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled))
+	case <-time.After(time.Second):
+		t.Fatal("call was not stopped at the callers' deadline")
+	}
+}
+
+func TestGroup_Do_CallerWithoutDeadline(t *testing.T) {
+	g := singleflight.NewGroup[string, int]()
+
+	_, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return 0, nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,61 @@
+package tlsconfig
+
+import (
+	"context"
+	"net"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ClientCredentials returns the transport credentials of a gRPC client,
+// plaintext unless cfg enables TLS.
+func ClientCredentials(cfg *config.TLS) (credentials.TransportCredentials, error) {
+	if cfg == nil || !cfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	source, err := NewSource(Options{
+		CAFile:     cfg.CAFile,
+		CertFile:   cfg.CertFile,
+		KeyFile:    cfg.KeyFile,
+		ServerName: cfg.ServerName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCredentials(source), nil
+}
+
+// reloadingCredentials handshakes every new connection with the current
+// config of its source.
+type reloadingCredentials struct {
+	source *Source
+}
+
+func NewCredentials(source *Source) credentials.TransportCredentials {
+	return &reloadingCredentials{source: source}
+}
+
+func (c *reloadingCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.source.Config()).ClientHandshake(ctx, authority, conn)
+}
+
+func (c *reloadingCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.source.Config()).ServerHandshake(conn)
+}
+
+func (c *reloadingCredentials) Info() credentials.ProtocolInfo {
+	return credentials.NewTLS(c.source.Config()).Info()
+}
+
+func (c *reloadingCredentials) Clone() credentials.TransportCredentials {
+	return &reloadingCredentials{source: c.source}
+}
+
+// OverrideServerName is deprecated by gRPC, set config.TLS.ServerName instead.
+func (c *reloadingCredentials) OverrideServerName(string) error {
+	return nil
+}
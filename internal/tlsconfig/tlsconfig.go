@@ -0,0 +1,221 @@
+package tlsconfig
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+)
+
+const defaultReloadInterval = time.Second
+
+type Options struct {
+	// CAFile is a PEM bundle verifying the peer, empty uses the system roots.
+	CAFile string
+	// CertFile and KeyFile are presented to the peer, e.g. for mTLS.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name the server certificate is verified
+	// against, by default it is the host of the target.
+	ServerName string
+	// MinVersion defaults to TLS 1.2.
+	MinVersion uint16
+	// CipherSuites limits the TLS 1.0-1.2 cipher suites, nil uses Go's
+	// defaults. TLS 1.3 suites are not configurable.
+	CipherSuites []uint16
+	// ReloadInterval is how often the files are checked for changes.
+	ReloadInterval time.Duration
+}
+
+// Source builds a tls.Config from files and rebuilds it once they
+// change on disk, so rotated certificates are used for new connections
+// without a restart.
+type Source struct {
+	opts Options
+
+	mu        sync.Mutex
+	config    *tls.Config
+	contents  [][]byte
+	checkedAt time.Time
+}
+
+func NewSource(opts Options) (*Source, error) {
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return nil, errors.New("TLS certificate requires both a cert and a key file")
+	}
+	if opts.MinVersion == 0 {
+		opts.MinVersion = tls.VersionTLS12
+	}
+	if opts.ReloadInterval <= 0 {
+		opts.ReloadInterval = defaultReloadInterval
+	}
+
+	s := &Source{opts: opts}
+
+	contents, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	if s.config, err = s.build(contents); err != nil {
+		return nil, err
+	}
+	s.contents = contents
+	s.checkedAt = time.Now()
+
+	return s, nil
+}
+
+// Config returns the current config. A rotation that fails to load is logged
+// and the previous config is kept.
+func (s *Source) Config() *tls.Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.checkedAt) < s.opts.ReloadInterval {
+		return s.config
+	}
+	s.checkedAt = time.Now()
+
+	contents, err := s.read()
+	if err == nil && s.unchanged(contents) {
+		return s.config
+	}
+
+	var config *tls.Config
+	if err == nil {
+		config, err = s.build(contents)
+	}
+	if err != nil {
+		logger.Warn().Str("cert_file", s.opts.CertFile).Str("ca_file", s.opts.CAFile).Err(err).Msg("TLS reload failed, keeping the current certificates")
+		return s.config
+	}
+
+	logger.Info().Str("cert_file", s.opts.CertFile).Str("ca_file", s.opts.CAFile).Msg("TLS certificates reloaded")
+	s.config, s.contents = config, contents
+
+	return s.config
+}
+
+// ServerConfig returns a config for a TLS listener, every handshake uses the
+// current certificate of s. HTTP/2 is negotiated by net/http.
+func (s *Source) ServerConfig() *tls.Config {
+	config := s.Config().Clone()
+	config.RootCAs = nil
+	config.ServerName = ""
+	config.Certificates = nil
+	config.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		certs := s.Config().Certificates
+		if len(certs) == 0 {
+			return nil, errors.New("no TLS certificate configured")
+		}
+		return &certs[0], nil
+	}
+
+	return config
+}
+
+func (s *Source) read() ([][]byte, error) {
+	contents := make([][]byte, 0, 3)
+	for _, name := range []string{s.opts.CAFile, s.opts.CertFile, s.opts.KeyFile} {
+		if name == "" {
+			contents = append(contents, nil)
+			continue
+		}
+
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, b)
+	}
+
+	return contents, nil
+}
+
+func (s *Source) unchanged(contents [][]byte) bool {
+	for i := range contents {
+		if !bytes.Equal(contents[i], s.contents[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Source) build(contents [][]byte) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:   s.opts.MinVersion,
+		CipherSuites: s.opts.CipherSuites,
+		ServerName:   s.opts.ServerName,
+	}
+
+	if ca := contents[0]; ca != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", s.opts.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if cert, key := contents[1], contents[2]; cert != nil {
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS key pair %s: %w", s.opts.CertFile, err)
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+
+	return config, nil
+}
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseVersion parses a TLS version like "1.2", empty is TLS 1.2.
+func ParseVersion(version string) (uint16, error) {
+	if version == "" {
+		return tls.VersionTLS12, nil
+	}
+
+	v, ok := versions[version]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version %q", version)
+	}
+
+	return v, nil
+}
+
+// ParseCipherSuites parses cipher suite names like
+// "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", suites Go considers insecure are
+// rejected.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	byName := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		byName[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure TLS cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
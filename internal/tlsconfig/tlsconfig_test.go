@@ -0,0 +1,239 @@
+package tlsconfig_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/config"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type certificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newCertificate issues a certificate for name signed by parent, a nil parent
+// makes a self-signed CA.
+func newCertificate(t *testing.T, name string, parent *certificate) *certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &certificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, dir, name string, content []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+
+	return path
+}
+
+// startTLSBackend serves grpc_health_v1 with server's certificate and only
+// accepts clients with a certificate signed by ca.
+func startTLSBackend(t *testing.T, ca, server *certificate) string {
+	t.Helper()
+
+	pair, err := tls.X509KeyPair(server.certPEM, server.keyPEM)
+	require.NoError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestClientCredentials_MTLS(t *testing.T) {
+	ca := newCertificate(t, "ca", nil)
+	server := newCertificate(t, "upload.internal", ca)
+	client := newCertificate(t, "gateway", ca)
+	otherCA := newCertificate(t, "other-ca", nil)
+
+	addr := startTLSBackend(t, ca, server)
+
+	dir := t.TempDir()
+	caFile := writeFile(t, dir, "ca.pem", ca.certPEM)
+	certFile := writeFile(t, dir, "client.pem", client.certPEM)
+	keyFile := writeFile(t, dir, "client-key.pem", client.keyPEM)
+	otherCAFile := writeFile(t, dir, "other-ca.pem", otherCA.certPEM)
+
+	tests := []struct {
+		name      string
+		cfg       *config.TLS
+		expectErr bool
+	}{
+		{
+			name: "mutual TLS with server name override",
+			cfg:  &config.TLS{Enabled: true, CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "upload.internal"},
+		},
+		{
+			name:      "server requires a client certificate",
+			cfg:       &config.TLS{Enabled: true, CAFile: caFile, ServerName: "upload.internal"},
+			expectErr: true,
+		},
+		{
+			name:      "server certificate not signed by the CA bundle",
+			cfg:       &config.TLS{Enabled: true, CAFile: otherCAFile, CertFile: certFile, KeyFile: keyFile, ServerName: "upload.internal"},
+			expectErr: true,
+		},
+		{
+			name:      "server name does not match",
+			cfg:       &config.TLS{Enabled: true, CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "video-catalog.internal"},
+			expectErr: true,
+		},
+		{
+			name:      "plaintext",
+			cfg:       &config.TLS{Enabled: false},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := tlsconfig.ClientCredentials(tt.cfg)
+			require.NoError(t, err)
+
+			conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewSource_InvalidOptions(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCertificate(t, "ca", nil)
+	caFile := writeFile(t, dir, "ca.pem", ca.certPEM)
+	certFile := writeFile(t, dir, "cert.pem", ca.certPEM)
+	invalid := writeFile(t, dir, "invalid.pem", []byte("not a certificate"))
+
+	tests := []struct {
+		name string
+		opts tlsconfig.Options
+	}{
+		{name: "cert without key", opts: tlsconfig.Options{CAFile: caFile, CertFile: certFile}},
+		{name: "missing CA file", opts: tlsconfig.Options{CAFile: filepath.Join(dir, "missing.pem")}},
+		{name: "CA file without certificates", opts: tlsconfig.Options{CAFile: invalid}},
+		{name: "invalid key pair", opts: tlsconfig.Options{CertFile: certFile, KeyFile: invalid}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tlsconfig.NewSource(tt.opts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSource_ReloadsRotatedCertificates(t *testing.T) {
+	ca := newCertificate(t, "ca", nil)
+	first := newCertificate(t, "gateway", ca)
+	second := newCertificate(t, "gateway", ca)
+
+	dir := t.TempDir()
+	caFile := writeFile(t, dir, "ca.pem", ca.certPEM)
+	certFile := writeFile(t, dir, "client.pem", first.certPEM)
+	keyFile := writeFile(t, dir, "client-key.pem", first.keyPEM)
+
+	source, err := tlsconfig.NewSource(tlsconfig.Options{CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ReloadInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	leaf := func() []byte {
+		return source.Config().Certificates[0].Certificate[0]
+	}
+	assert.Equal(t, first.cert.Raw, leaf())
+
+	writeFile(t, dir, "client.pem", second.certPEM)
+	writeFile(t, dir, "client-key.pem", second.keyPEM)
+
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(second.cert.Raw, leaf())
+	}, time.Second, 5*time.Millisecond, "rotated certificate should be loaded")
+
+	// a half written rotation keeps the last good certificate
+	writeFile(t, dir, "client.pem", first.certPEM)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, second.cert.Raw, leaf())
+}
@@ -0,0 +1,142 @@
+package transcoder
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// setField assigns values to the field at path, nested fields are separated
+// by dots, e.g. "page.size". Repeated fields take every value, singular
+// fields the last one.
+func setField(msg protoreflect.Message, path string, values []string) error {
+	names := strings.Split(path, ".")
+
+	for i, name := range names {
+		field := lookupField(msg.Descriptor(), name)
+		if field == nil {
+			return fmt.Errorf("unknown field %q in %s", name, msg.Descriptor().FullName())
+		}
+
+		if i < len(names)-1 {
+			if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+				return fmt.Errorf("field %q is not a message", name)
+			}
+			msg = msg.Mutable(field).Message()
+			continue
+		}
+
+		if field.IsMap() || field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+			return fmt.Errorf("field %q can not be set from a string", name)
+		}
+
+		if field.IsList() {
+			list := msg.Mutable(field).List()
+			for _, v := range values {
+				value, err := parseScalar(field, v)
+				if err != nil {
+					return err
+				}
+				list.Append(value)
+			}
+			return nil
+		}
+
+		if len(values) == 0 {
+			return nil
+		}
+
+		value, err := parseScalar(field, values[len(values)-1])
+		if err != nil {
+			return err
+		}
+		msg.Set(field, value)
+	}
+
+	return nil
+}
+
+func lookupField(desc protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := desc.Fields()
+
+	if f := fields.ByName(protoreflect.Name(name)); f != nil {
+		return f
+	}
+
+	return fields.ByJSONName(name)
+}
+
+func parseScalar(field protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	invalid := func(err error) (protoreflect.Value, error) {
+		return protoreflect.Value{}, fmt.Errorf("invalid value %q for %s field %q: %w", s, field.Kind(), field.Name(), err)
+	}
+
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			if b, err = base64.URLEncoding.DecodeString(s); err != nil {
+				return invalid(err)
+			}
+		}
+		return protoreflect.ValueOfBytes(b), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfUint64(n), nil
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfFloat32(float32(f)), nil
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.EnumKind:
+		if v := field.Enum().Values().ByName(protoreflect.Name(s)); v != nil {
+			return protoreflect.ValueOfEnum(v.Number()), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || field.Enum().Values().ByNumber(protoreflect.EnumNumber(n)) == nil {
+			return invalid(fmt.Errorf("not a value of %s", field.Enum().FullName()))
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	}
+
+	return invalid(fmt.Errorf("unsupported kind"))
+}
@@ -0,0 +1,169 @@
+package transcoder_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/transcoder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// echoFiles describes a test.EchoService whose Echo rpc returns its request,
+// the messages are not compiled into the binary so they go through dynamicpb.
+func echoFiles(t *testing.T) *protoregistry.Files {
+	t.Helper()
+
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("echo.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Order"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("ASC"), Number: proto.Int32(0)},
+				{Name: proto.String("DESC"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Page"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("size", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, "")},
+			},
+			{
+				Name: proto.String("Request"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("query", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					field("ids", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, repeated, ""),
+					field("order", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional, ".test.Order"),
+					field("page", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".test.Page"),
+					field("published", 5, descriptorpb.FieldDescriptorProto_TYPE_BOOL, optional, ""),
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("EchoService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Echo"),
+				InputType:  proto.String(".test.Request"),
+				OutputType: proto.String(".test.Request"),
+			}},
+		}},
+	}}})
+	require.NoError(t, err)
+
+	return files
+}
+
+func newEchoRouter(t *testing.T, path string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	files := echoFiles(t)
+	desc, err := files.FindDescriptorByName("test.Request")
+	require.NoError(t, err)
+	requestDesc := desc.(protoreflect.MessageDescriptor)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+		msg := dynamicpb.NewMessage(requestDesc)
+		if err := stream.RecvMsg(msg); err != nil {
+			return err
+		}
+		return stream.SendMsg(msg)
+	}))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	tc := transcoder.New(map[string]transcoder.Backend{
+		"echo": {Conn: conn, Service: "test.EchoService", Resolver: files},
+	})
+
+	handler, err := tc.Handler("echo", "Echo")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET(path, handler)
+
+	return router
+}
+
+func TestTranscoder_Fields(t *testing.T) {
+	router := newEchoRouter(t, "/echo/:query")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/echo/path?query=ignored&ids=1&ids=2&order=DESC&page.size=20&published=true", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{
+		"query":     "path",
+		"ids":       []any{float64(1), float64(2)},
+		"order":     "DESC",
+		"page":      map[string]any{"size": float64(20)},
+		"published": true,
+	}, body)
+}
+
+func TestTranscoder_InvalidFields(t *testing.T) {
+	router := newEchoRouter(t, "/echo")
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown field", "missing=1"},
+		{"not a number", "ids=abc"},
+		{"unknown enum value", "order=RANDOM"},
+		{"message from string", "page=1"},
+		{"scalar traversal", "query.length=1"},
+		{"not a bool", "published=maybe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/echo?"+tt.query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package transcoder
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// LoadFromReflection fetches the descriptors of services, and every file they
+// depend on, through the gRPC server reflection API of the backend. It lets
+// the gateway transcode RPCs it has no compiled *.pb.go files for.
+func LoadFromReflection(ctx context.Context, conn grpc.ClientConnInterface, services ...string) (*protoregistry.Files, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	files := map[string]*descriptorpb.FileDescriptorProto{}
+
+	request := func(req *reflectionpb.ServerReflectionRequest) error {
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		if e := res.GetErrorResponse(); e != nil {
+			return fmt.Errorf("server reflection: %s", e.GetErrorMessage())
+		}
+
+		for _, b := range res.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(b, file); err != nil {
+				return err
+			}
+			files[file.GetName()] = file
+		}
+
+		return nil
+	}
+
+	for _, service := range services {
+		err := request(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", service, err)
+		}
+	}
+
+	// servers may leave out dependencies they already sent on this stream or
+	// consider well known, ask for whatever is still missing
+	for missing := missingDependencies(files); len(missing) > 0; missing = missingDependencies(files) {
+		for _, name := range missing {
+			err := request(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("file %q: %w", name, err)
+			}
+			if _, ok := files[name]; !ok {
+				return nil, fmt.Errorf("file %q: not returned by server reflection", name)
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, file)
+	}
+
+	return protodesc.NewFiles(set)
+}
+
+func missingDependencies(files map[string]*descriptorpb.FileDescriptorProto) []string {
+	var missing []string
+	for _, file := range files {
+		for _, dep := range file.GetDependency() {
+			if _, ok := files[dep]; !ok {
+				missing = append(missing, dep)
+			}
+		}
+	}
+
+	return missing
+}
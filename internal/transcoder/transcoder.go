@@ -0,0 +1,234 @@
+package transcoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/helper"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/lib/logger"
+	authpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/authentication/authentication"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var ErrUnknownBackend = errors.New("unknown backend service")
+
+// DefaultMaxBodyBytes bounds request bodies when Options leaves it unset.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Resolver finds service and message descriptors, *protoregistry.Files
+// satisfies it for both compiled and reflected descriptors.
+type Resolver interface {
+	FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error)
+}
+
+// Backend is a gRPC service requests can be transcoded onto.
+type Backend struct {
+	Conn grpc.ClientConnInterface
+	// Service is the fully qualified proto service name, e.g. "upload.UploadService".
+	Service string
+	// Resolver defaults to the descriptors compiled into the binary.
+	Resolver Resolver
+	Timeout  time.Duration
+}
+
+// Transcoder exposes unary RPCs as JSON endpoints. The request message is
+// built from the JSON body, the query string and the path params, in that
+// order, and the response is marshalled with protojson.
+type Transcoder struct {
+	backends     map[string]Backend
+	maxBodyBytes int64
+}
+
+type Options struct {
+	// MaxBodyBytes is the largest request body read, larger ones are
+	// answered with 413. Defaults to DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+}
+
+func New(backends map[string]Backend) *Transcoder {
+	return NewWithOptions(backends, Options{})
+}
+
+func NewWithOptions(backends map[string]Backend, opts Options) *Transcoder {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	return &Transcoder{backends: backends, maxBodyBytes: opts.MaxBodyBytes}
+}
+
+var (
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true}
+)
+
+// Handler returns the handler for rpc of the given backend, it fails if the
+// rpc does not exist or is not unary.
+func (t *Transcoder) Handler(service string, rpc string) (gin.HandlerFunc, error) {
+	method, err := t.Method(service, rpc)
+	if err != nil {
+		return nil, err
+	}
+	backend := t.backends[service]
+
+	fullMethod := fmt.Sprintf("/%s/%s", backend.Service, method.Name())
+
+	return func(c *gin.Context) {
+		in := newMessage(method.Input())
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, t.maxBodyBytes)
+		}
+		if err := decodeRequest(c, in); err != nil {
+			logger.Ctx(c.Request.Context()).Warn().
+				Str("service", service).
+				Str("method", rpc).
+				Err(err).
+				Msg("Unable to transcode request")
+
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				helper.RespondWithError(c, http.StatusRequestEntityTooLarge, helper.PrepareResponse(constant.MessageRequestEntityTooLarge, gin.H{}))
+				return
+			}
+			helper.RespondWithError(c, http.StatusBadRequest, helper.PrepareResponse(constant.MessageBadRequest, gin.H{}))
+			return
+		}
+
+		ctx := c.Request.Context()
+		if backend.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, backend.Timeout)
+			defer cancel()
+		}
+		ctx = outgoingMetadata(ctx, c)
+
+		out := newMessage(method.Output())
+		if err := backend.Conn.Invoke(ctx, fullMethod, in, out); err != nil {
+			status, res := helper.PrepareResponseFromGRPCError(err, gin.H{})
+			helper.RespondWithError(c, status, res)
+			return
+		}
+
+		data, err := marshalOptions.Marshal(out)
+		if err != nil {
+			logger.Ctx(ctx).Error().
+				Str("service", service).
+				Str("method", rpc).
+				Err(err).
+				Msg("Unable to marshal response")
+			helper.RespondWithError(c, http.StatusInternalServerError, helper.PrepareResponse(constant.MessageInternalServerError, gin.H{}))
+			return
+		}
+
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+	}, nil
+}
+
+// Method returns the descriptor of rpc of the given backend.
+func (t *Transcoder) Method(service string, rpc string) (protoreflect.MethodDescriptor, error) {
+	backend, ok := t.backends[service]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownBackend, service)
+	}
+
+	return backend.method(rpc)
+}
+
+func (b Backend) method(rpc string) (protoreflect.MethodDescriptor, error) {
+	resolver := b.Resolver
+	if resolver == nil {
+		resolver = protoregistry.GlobalFiles
+	}
+
+	desc, err := resolver.FindDescriptorByName(protoreflect.FullName(b.Service))
+	if err != nil {
+		return nil, fmt.Errorf("service %q: %w", b.Service, err)
+	}
+
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", b.Service)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(rpc))
+	if method == nil {
+		return nil, fmt.Errorf("service %q has no rpc %q", b.Service, rpc)
+	}
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, fmt.Errorf("rpc %q of %q is streaming, only unary rpcs can be transcoded", rpc, b.Service)
+	}
+
+	return method, nil
+}
+
+// newMessage prefers the generated type so responses keep their Go types,
+// descriptors that only came from server reflection fall back to dynamicpb.
+func newMessage(desc protoreflect.MessageDescriptor) proto.Message {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName()); err == nil {
+		return mt.New().Interface()
+	}
+
+	return dynamicpb.NewMessage(desc)
+}
+
+func decodeRequest(c *gin.Context, in proto.Message) error {
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return err
+		}
+		if len(body) > 0 {
+			if err := unmarshalOptions.Unmarshal(body, in); err != nil {
+				return err
+			}
+		}
+	}
+
+	for key, values := range c.Request.URL.Query() {
+		if err := setField(in.ProtoReflect(), key, values); err != nil {
+			return fmt.Errorf("query %q: %w", key, err)
+		}
+	}
+
+	for _, param := range c.Params {
+		if err := setField(in.ProtoReflect(), param.Key, []string{param.Value}); err != nil {
+			return fmt.Errorf("path param %q: %w", param.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// outgoingMetadata forwards the caller's credentials the same way the
+// hand-written clients do.
+func outgoingMetadata(ctx context.Context, c *gin.Context) context.Context {
+	pairs := []string{}
+
+	if token := c.GetHeader("Authorization"); token != "" {
+		pairs = append(pairs, constant.GRPCHeaderAuthorization, token)
+	}
+	if user, exists := c.Get(constant.AuthUser); exists {
+		if u, ok := user.(*authpb.User); ok {
+			pairs = append(pairs, constant.GRPCHeaderUserId, strconv.Itoa(int(u.Id)))
+		}
+	}
+
+	if len(pairs) == 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
@@ -0,0 +1,243 @@
+package transcoder_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/constant"
+	videocatalogpb "github.com/sagarmaheshwary/microservices-api-gateway/internal/proto/video_catalog"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/transcoder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type videoCatalogServer struct {
+	videocatalogpb.UnimplementedVideoCatalogServiceServer
+	authorization []string
+}
+
+func (s *videoCatalogServer) FindById(ctx context.Context, in *videocatalogpb.FindByIdRequest) (*videocatalogpb.FindByIdResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorization = md.Get(constant.GRPCHeaderAuthorization)
+
+	if in.Id != 1 {
+		return nil, status.Error(codes.NotFound, "video not found")
+	}
+
+	return &videocatalogpb.FindByIdResponse{
+		Message: constant.MessageOK,
+		Data: &videocatalogpb.FindByIdResponseData{
+			Video:       &videocatalogpb.Video{Id: in.Id, Title: "title", ThumbnailUrl: "thumbnail"},
+			ManifestUrl: "manifest",
+		},
+	}, nil
+}
+
+func newBackend(t *testing.T) (*grpc.ClientConn, *videoCatalogServer) {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	impl := &videoCatalogServer{}
+	videocatalogpb.RegisterVideoCatalogServiceServer(server, impl)
+	reflection.Register(server)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, impl
+}
+
+func TestTranscoder_Handler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	conn, impl := newBackend(t)
+	tc := transcoder.New(map[string]transcoder.Backend{
+		constant.ServiceVideoCatalog: {Conn: conn, Service: videocatalogpb.VideoCatalogService_ServiceDesc.ServiceName},
+	})
+
+	handler, err := tc.Handler(constant.ServiceVideoCatalog, "FindById")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/videos/:id", handler)
+	router.GET("/videos", handler)
+	router.POST("/videos", handler)
+
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		body         string
+		expectStatus int
+		expectBody   map[string]any
+	}{
+		{
+			name:         "path param",
+			method:       http.MethodGet,
+			target:       "/videos/1",
+			expectStatus: http.StatusOK,
+			expectBody: map[string]any{
+				"message": constant.MessageOK,
+				"data": map[string]any{
+					"video":        map[string]any{"id": float64(1), "title": "title", "thumbnail_url": "thumbnail"},
+					"manifest_url": "manifest",
+				},
+			},
+		},
+		{
+			name:         "query param",
+			method:       http.MethodGet,
+			target:       "/videos?id=1",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "json body",
+			method:       http.MethodPost,
+			target:       "/videos",
+			body:         `{"id": 1}`,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "invalid path param",
+			method:       http.MethodGet,
+			target:       "/videos/abc",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown query param",
+			method:       http.MethodGet,
+			target:       "/videos?page=2",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "grpc error",
+			method:       http.MethodGet,
+			target:       "/videos/2",
+			expectStatus: http.StatusNotFound,
+			expectBody:   map[string]any{"message": constant.MessageNotFound, "code": "NOT_FOUND", "data": map[string]any{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectBody != nil {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, tt.expectBody, body)
+			}
+		})
+	}
+
+	assert.Equal(t, []string{"Bearer token"}, impl.authorization)
+}
+
+func TestTranscoder_MaxBodyBytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	conn, _ := newBackend(t)
+	tc := transcoder.NewWithOptions(map[string]transcoder.Backend{
+		constant.ServiceVideoCatalog: {Conn: conn, Service: videocatalogpb.VideoCatalogService_ServiceDesc.ServiceName},
+	}, transcoder.Options{MaxBodyBytes: 16})
+
+	handler, err := tc.Handler(constant.ServiceVideoCatalog, "FindById")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.POST("/videos", handler)
+
+	tests := []struct {
+		name          string
+		body          string
+		expectStatus  int
+		expectMessage string
+	}{
+		{name: "within the limit", body: `{"id": 1}`, expectStatus: http.StatusOK, expectMessage: constant.MessageOK},
+		{name: "over the limit", body: `{"id": 1, "padding": "` + strings.Repeat("a", 64) + `"}`, expectStatus: http.StatusRequestEntityTooLarge, expectMessage: constant.MessageRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/videos", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectMessage, body["message"])
+		})
+	}
+}
+
+func TestTranscoder_HandlerErrors(t *testing.T) {
+	conn, _ := newBackend(t)
+	tc := transcoder.New(map[string]transcoder.Backend{
+		constant.ServiceVideoCatalog: {Conn: conn, Service: videocatalogpb.VideoCatalogService_ServiceDesc.ServiceName},
+		"missing":                    {Conn: conn, Service: "missing.MissingService"},
+	})
+
+	_, err := tc.Handler(constant.ServiceUpload, "CreatePresignedUrl")
+	assert.ErrorIs(t, err, transcoder.ErrUnknownBackend)
+
+	_, err = tc.Handler(constant.ServiceVideoCatalog, "Delete")
+	assert.ErrorContains(t, err, `has no rpc "Delete"`)
+
+	_, err = tc.Handler("missing", "Find")
+	assert.ErrorContains(t, err, `service "missing.MissingService"`)
+}
+
+func TestLoadFromReflection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	conn, _ := newBackend(t)
+	serviceName := videocatalogpb.VideoCatalogService_ServiceDesc.ServiceName
+
+	files, err := transcoder.LoadFromReflection(context.Background(), conn, serviceName)
+	require.NoError(t, err)
+
+	tc := transcoder.New(map[string]transcoder.Backend{
+		constant.ServiceVideoCatalog: {Conn: conn, Service: serviceName, Resolver: files},
+	})
+
+	handler, err := tc.Handler(constant.ServiceVideoCatalog, "FindById")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/videos/:id", handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/videos/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"manifest_url":"manifest"`)
+
+	_, err = transcoder.LoadFromReflection(context.Background(), conn, "missing.MissingService")
+	assert.Error(t, err)
+}
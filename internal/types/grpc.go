@@ -4,10 +4,17 @@ import (
 	authrpc "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/authentication"
 	uploadrpc "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/upload"
 	videocatalogrpc "github.com/sagarmaheshwary/microservices-api-gateway/internal/grpc/video-catalog"
+	"google.golang.org/grpc"
 )
 
 type GRPCClients struct {
 	AuthClient         authrpc.AuthenticationService
 	UploadClient       uploadrpc.UploadService
 	VideoCatalogClient videocatalogrpc.VideoCatalogService
+	// Conns holds the connections of the clients by service name, routes
+	// without a handler are transcoded onto them.
+	Conns map[string]grpc.ClientConnInterface
+	// Ready reports by service name whether a lazily connected backend has
+	// been reached, services without an entry are always ready.
+	Ready map[string]func() bool
 }
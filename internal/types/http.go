@@ -5,9 +5,9 @@ type AuthorizationHeader struct {
 }
 
 type RegisterInput struct {
-	Name     string `json:"name" binding:"required"`
+	Name     string `json:"name" binding:"required,name"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Password string `json:"password" binding:"required,password"`
 }
 
 type RegisterValidationError struct {
@@ -35,10 +35,10 @@ type VerifyTokenValidationError struct {
 }
 
 type UploadedWebhookInput struct {
-	VideoId     string `json:"video_id" binding:"required"`
-	ThumbnailId string `json:"thumbnail_id" binding:"required"`
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	VideoId     string `json:"video_id" binding:"required,uuid"`
+	ThumbnailId string `json:"thumbnail_id" binding:"required,uuid"`
+	Title       string `json:"title" binding:"required,max=255"`
+	Description string `json:"description" binding:"required,max=5000"`
 }
 
 type UploadedWebhookValidationError struct {
@@ -47,3 +47,23 @@ type UploadedWebhookValidationError struct {
 	Title       []string `json:"title"`
 	Description []string `json:"description"`
 }
+
+type FindAllVideosInput struct {
+	Page       int32  `form:"page" binding:"omitempty,min=1"`
+	Limit      int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor     string `form:"cursor" binding:"omitempty,excluded_with=Page,max=512"`
+	SortBy     string `form:"sort_by" binding:"omitempty,oneof=published_at duration"`
+	SortOrder  string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	UserId     int32  `form:"user_id" binding:"omitempty,min=1"`
+	Resolution string `form:"resolution" binding:"omitempty,alphanum,max=16"`
+}
+
+type FindAllVideosValidationError struct {
+	Page       []string `json:"page"`
+	Limit      []string `json:"limit"`
+	Cursor     []string `json:"cursor"`
+	SortBy     []string `json:"sort_by"`
+	SortOrder  []string `json:"sort_order"`
+	UserId     []string `json:"user_id"`
+	Resolution []string `json:"resolution"`
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+)
+
+// Message templates live in the "validation" section of the i18n catalogs,
+// keyed by rule. "{field}" and "{param}" are replaced by the field name and
+// the rule parameter, e.g. the 255 of max=255, and "{min}" and "{max}" by the
+// bounds of the custom rules. A "<rule>.string" entry is preferred for string
+// fields, where min and max are lengths.
+var bounds = map[string][2]int{
+	"name":            {NameMinLength, NameMaxLength},
+	"password.length": {PasswordMinLength, PasswordMaxLength},
+}
+
+// Message renders the template of key in locale, a rule without one is
+// reported as invalid.
+func Message(locale, key, field, param string) string {
+	template, ok := i18n.Validation(locale, key)
+	if !ok {
+		template, _ = i18n.Validation(locale, "invalid")
+	}
+
+	b := bounds[key]
+
+	return strings.NewReplacer(
+		"{field}", field,
+		"{param}", param,
+		"{min}", strconv.Itoa(b[0]),
+		"{max}", strconv.Itoa(b[1]),
+	).Replace(template)
+}
+
+func key(tag string, kind reflect.Kind) string {
+	if kind == reflect.String {
+		if _, ok := i18n.Validation(i18n.DefaultLocale, tag+".string"); ok {
+			return tag + ".string"
+		}
+	}
+
+	return tag
+}
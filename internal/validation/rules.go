@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	PasswordMinLength = 8
+	// bcrypt ignores everything after 72 bytes
+	PasswordMaxLength = 72
+	NameMinLength     = 2
+	NameMaxLength     = 100
+)
+
+var rules = map[string]validator.Func{
+	"password": func(fl validator.FieldLevel) bool {
+		return len(passwordProblems(fl.Field().Interface())) == 0
+	},
+	"name": func(fl validator.FieldLevel) bool {
+		n := utf8.RuneCountInString(strings.TrimSpace(fl.Field().String()))
+		return n >= NameMinLength && n <= NameMaxLength
+	},
+}
+
+// expanders split a failed rule into the message keys of its requirements.
+var expanders = map[string]func(value any) []string{
+	"password": passwordProblems,
+}
+
+func passwordProblems(value any) []string {
+	password, _ := value.(string)
+
+	var problems []string
+	if utf8.RuneCountInString(password) < PasswordMinLength || len(password) > PasswordMaxLength {
+		problems = append(problems, "password.length")
+	}
+
+	classes := []struct {
+		key string
+		is  func(rune) bool
+	}{
+		{"password.lower", unicode.IsLower},
+		{"password.upper", unicode.IsUpper},
+		{"password.digit", unicode.IsDigit},
+	}
+	for _, class := range classes {
+		if !strings.ContainsFunc(password, class.is) {
+			problems = append(problems, class.key)
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,141 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// The rules are registered on gin's validator so every ShouldBind* call of
+// the gateway can use them in binding tags.
+func init() {
+	if err := Register(engine()); err != nil {
+		panic(err)
+	}
+}
+
+func engine() *validator.Validate {
+	return binding.Validator.Engine().(*validator.Validate)
+}
+
+// Register adds the custom rules of the gateway to v.
+func Register(v *validator.Validate) error {
+	for tag, rule := range rules {
+		if err := v.RegisterValidation(tag, rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Failure is a rule a struct field does not satisfy.
+type Failure struct {
+	// Field is the name of the struct field, e.g. "VideoId".
+	Field string
+	Tag   string
+	Param string
+	Kind  reflect.Kind
+	Value any
+}
+
+// Messages describes f for field in locale, one message per violated
+// requirement, e.g. a password missing both a number and an uppercase letter
+// gets two.
+func (f Failure) Messages(locale, field string) []string {
+	if expand, ok := expanders[f.Tag]; ok {
+		keys := expand(f.Value)
+		messages := make([]string, 0, len(keys))
+		for _, key := range keys {
+			messages = append(messages, Message(locale, key, field, f.Param))
+		}
+		return messages
+	}
+
+	return []string{Message(locale, key(f.Tag, f.Kind), field, f.Param)}
+}
+
+// Failures returns every failing rule of every field in err. validator stops
+// at the first failing rule of a field, the rules after it in the binding tag
+// of in are checked here. Rules comparing fields, like excluded_with, can't
+// be checked on their own and are only reported when validator reported them.
+func Failures(err error, in any) []Failure {
+	ve, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil
+	}
+
+	t := reflect.TypeOf(in)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var failures []Failure
+	for _, fe := range ve {
+		failures = append(failures, Failure{
+			Field: fe.StructField(),
+			Tag:   fe.Tag(),
+			Param: fe.Param(),
+			Kind:  fe.Kind(),
+			Value: fe.Value(),
+		})
+
+		// a missing value fails every other rule, they add nothing
+		if fe.Tag() == "required" || t == nil || t.Kind() != reflect.Struct {
+			continue
+		}
+		sf, ok := t.FieldByName(fe.StructField())
+		if !ok {
+			continue
+		}
+
+		failures = append(failures, remaining(fe, sf.Tag.Get("binding"))...)
+	}
+
+	return failures
+}
+
+// remaining checks the rules of tags after the one fe failed.
+func remaining(fe validator.FieldError, tags string) []Failure {
+	var failures []Failure
+
+	found := false
+	for _, rule := range strings.Split(tags, ",") {
+		tag, param, _ := strings.Cut(rule, "=")
+		if !found {
+			found = tag == fe.Tag()
+			continue
+		}
+		if !standalone(rule) {
+			continue
+		}
+
+		if err := engine().Var(fe.Value(), rule); err != nil {
+			failures = append(failures, Failure{
+				Field: fe.StructField(),
+				Tag:   tag,
+				Param: param,
+				Kind:  fe.Kind(),
+				Value: fe.Value(),
+			})
+		}
+	}
+
+	return failures
+}
+
+// standalone reports whether rule only depends on the value of its field.
+func standalone(rule string) bool {
+	tag, _, _ := strings.Cut(rule, "=")
+	switch {
+	case tag == "omitempty", tag == "dive", strings.Contains(rule, "|"):
+		return false
+	case strings.HasSuffix(tag, "field"), strings.Contains(tag, "_with"),
+		strings.Contains(tag, "_if"), strings.Contains(tag, "_unless"):
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,131 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/i18n"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/types"
+	"github.com/sagarmaheshwary/microservices-api-gateway/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// messages validates in with gin's validator and collects the messages by
+// struct field.
+func messages(t *testing.T, in any) map[string][]string {
+	t.Helper()
+
+	err := binding.Validator.ValidateStruct(in)
+	if err == nil {
+		return nil
+	}
+
+	got := map[string][]string{}
+	for _, f := range validation.Failures(err, in) {
+		got[f.Field] = append(got[f.Field], f.Messages(i18n.DefaultLocale, strings.ToLower(f.Field))...)
+	}
+	require.NotEmpty(t, got, "validation error without failures: %v", err)
+
+	return got
+}
+
+func TestRules_RegisterInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       types.RegisterInput
+		expected map[string][]string
+	}{
+		{
+			name: "valid",
+			in:   types.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "Secret123"},
+		},
+		{
+			name: "password missing every character class but lowercase",
+			in:   types.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "secretpassword"},
+			expected: map[string][]string{
+				"Password": {"password must contain an uppercase letter", "password must contain a number"},
+			},
+		},
+		{
+			name: "password too long",
+			in:   types.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "Aa1" + strings.Repeat("a", 70)},
+			expected: map[string][]string{
+				"Password": {"password must be between 8 and 72 characters"},
+			},
+		},
+		{
+			name: "name of whitespace",
+			in:   types.RegisterInput{Name: "   ", Email: "jane@example.com", Password: "Secret123"},
+			expected: map[string][]string{
+				"Name": {"name must be between 2 and 100 characters"},
+			},
+		},
+		{
+			name: "name too long",
+			in:   types.RegisterInput{Name: strings.Repeat("é", 101), Email: "jane@example.com", Password: "Secret123"},
+			expected: map[string][]string{
+				"Name": {"name must be between 2 and 100 characters"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, messages(t, &tt.in))
+		})
+	}
+}
+
+func TestRules_UploadedWebhookInput(t *testing.T) {
+	valid := types.UploadedWebhookInput{
+		VideoId:     "0f3c7a56-1d2e-4b8a-9c4f-5e6d7a8b9c0d",
+		ThumbnailId: "7b1e2c3d-4f5a-4b6c-8d7e-9f0a1b2c3d4e",
+		Title:       strings.Repeat("t", 255),
+		Description: strings.Repeat("d", 5000),
+	}
+	assert.Nil(t, messages(t, &valid))
+
+	invalid := valid
+	invalid.VideoId = "42"
+	invalid.Title = strings.Repeat("t", 256)
+
+	assert.Equal(t, map[string][]string{
+		"VideoId": {"videoid must be a valid UUID"},
+		"Title":   {"title must be at most 255 characters"},
+	}, messages(t, &invalid))
+}
+
+func TestFailures_CollectsEveryRuleOfAField(t *testing.T) {
+	type input struct {
+		Code string `binding:"omitempty,alphanum,min=4,max=6"`
+	}
+
+	// validator stops at alphanum, min is still reported
+	assert.Equal(t, map[string][]string{
+		"Code": {"code must only contain letters and numbers", "code must be at least 4 characters"},
+	}, messages(t, &input{Code: "a-"}))
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		locale   string
+		key      string
+		param    string
+		expected string
+	}{
+		{"en", "required", "", "email is required"},
+		{"en", "max.string", "255", "email must be at most 255 characters"},
+		{"en", "password.digit", "", "email must contain a number"},
+		{"en", "password.length", "", "email must be between 8 and 72 characters"},
+		{"en", "unknown", "", "email is invalid"},
+		{"es", "max.string", "255", "email debe tener como máximo 255 caracteres"},
+		{"fr", "name", "", "email doit contenir entre 2 et 100 caractères"},
+		{"de", "required", "", "email is required"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, validation.Message(tt.locale, tt.key, "email", tt.param))
+	}
+}